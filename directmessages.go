@@ -0,0 +1,75 @@
+package gitter
+
+import "context"
+
+// OpenDirectMessage returns the one-to-one room with the user, creating it if needed.
+// The username is checked first, so passing the name of an org or a room
+// fails without joining anything. The room ID is cached by username, so
+// later calls fetch the room by ID.
+func (gitter *Gitter) OpenDirectMessage(username string) (*Room, error) {
+	return gitter.OpenDirectMessageContext(context.Background(), username)
+}
+
+// OpenDirectMessageContext is like OpenDirectMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) OpenDirectMessageContext(ctx context.Context, username string) (*Room, error) {
+
+	if roomID, ok := gitter.cachedRoomID(directMessageKey(username)); ok {
+		return gitter.GetRoomContext(ctx, roomID)
+	}
+
+	return gitter.openDirectMessage(ctx, username)
+}
+
+func (gitter *Gitter) openDirectMessage(ctx context.Context, username string) (*Room, error) {
+
+	_, err := gitter.GetUserByUsernameContext(ctx, username)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	// The URI of a one-to-one room is the username of the other user
+	room, err := gitter.joinRoomByURI(ctx, username)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	if !room.OneToOne {
+		err = APIError{What: "Not a one-to-one room: " + username}
+		gitter.log(err)
+		return nil, err
+	}
+
+	gitter.cacheRoomID(directMessageKey(username), *room)
+	return room, nil
+}
+
+// directMessageKey is the cache key of the one-to-one room with the user. It
+// can't clash with room URIs, so an org room looked up by the same name is
+// never mistaken for it.
+func directMessageKey(username string) string {
+	return "@" + username
+}
+
+// SendDirectMessage sends a private message to the user.
+// Once the one-to-one room is known, only the message is sent.
+func (gitter *Gitter) SendDirectMessage(username, text string) (*Message, error) {
+	return gitter.SendDirectMessageContext(context.Background(), username, text)
+}
+
+// SendDirectMessageContext is like SendDirectMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) SendDirectMessageContext(ctx context.Context, username, text string) (*Message, error) {
+
+	roomID, ok := gitter.cachedRoomID(directMessageKey(username))
+	if !ok {
+		room, err := gitter.openDirectMessage(ctx, username)
+		if err != nil {
+			gitter.log(err)
+			return nil, err
+		}
+		roomID = room.ID
+	}
+
+	return gitter.SendMessageContext(ctx, roomID, text)
+}
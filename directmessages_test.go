@@ -0,0 +1,191 @@
+package gitter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOpenDirectMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/fooBar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "username": "fooBar"}`)
+	})
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			URI string `json:"uri"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.URI != "fooBar" {
+			t.Errorf("Expected %v, got %v", "fooBar", payload.URI)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "dm1",
+                "oneToOne": true
+            }
+        `)
+	})
+
+	r, err := gitter.OpenDirectMessage("fooBar")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if r.ID != "dm1" {
+		t.Errorf("Expected %v, got %v", "dm1", r.ID)
+	}
+}
+
+func TestOpenDirectMessage_notAUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/gitterhq", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	var joined int32
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&joined, 1)
+	})
+
+	r, err := gitter.OpenDirectMessage("gitterhq")
+	if r != nil {
+		t.Errorf("Expected %v, got %v", nil, r)
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v, got %v", ErrNotFound, err)
+	}
+
+	if n := atomic.LoadInt32(&joined); n != 0 {
+		t.Errorf("Expected %v, got %v", 0, n)
+	}
+}
+
+func TestOpenDirectMessage_notOneToOne(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/fooBar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "username": "fooBar"}`)
+	})
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "uri": "fooBar",
+                "oneToOne": false
+            }
+        `)
+	})
+
+	r, err := gitter.OpenDirectMessage("fooBar")
+	if r != nil {
+		t.Errorf("Expected %v, got %v", nil, r)
+	}
+
+	if err == nil {
+		t.Errorf("Expected error, got %v", err)
+	}
+
+	if _, ok := gitter.cachedRoomID(directMessageKey("fooBar")); ok {
+		t.Errorf("Expected %v, got %v", false, ok)
+	}
+}
+
+func TestSendDirectMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/fooBar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "username": "fooBar"}`)
+	})
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "id": "dm1",
+                "oneToOne": true
+            }
+        `)
+	})
+
+	mux.HandleFunc("/rooms/dm1/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		var m Message
+		json.NewDecoder(r.Body).Decode(&m)
+		if m.Text != "hello" {
+			t.Errorf("Expected %v, got %v", "hello", m.Text)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "666",
+                "text": "hello"
+            }
+        `)
+	})
+
+	m, err := gitter.SendDirectMessage("fooBar", "hello")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if m.ID != "666" {
+		t.Errorf("Expected %v, got %v", "666", m.ID)
+	}
+}
+
+func TestSendDirectMessage_cached(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var lookups, joins, sends int32
+	mux.HandleFunc("/users/fooBar", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&lookups, 1)
+		fmt.Fprint(w, `{"id": "1", "username": "fooBar"}`)
+	})
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&joins, 1)
+		fmt.Fprint(w, `
+            {
+                "id": "dm1",
+                "oneToOne": true
+            }
+        `)
+	})
+
+	mux.HandleFunc("/rooms/dm1/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sends, 1)
+		fmt.Fprint(w, `{"id": "666"}`)
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := gitter.SendDirectMessage("fooBar", "hello")
+		if err != nil {
+			t.Errorf("Expected %v, got %v", nil, err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&lookups); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+
+	if n := atomic.LoadInt32(&joins); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+
+	if n := atomic.LoadInt32(&sends); n != 2 {
+		t.Errorf("Expected %v, got %v", 2, n)
+	}
+}
@@ -0,0 +1,102 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetSuggestedRooms returns rooms recommended to the user
+func (gitter *Gitter) GetSuggestedRooms(userID string) ([]Room, error) {
+	return gitter.GetSuggestedRoomsContext(context.Background(), userID)
+}
+
+// GetSuggestedRoomsContext is like GetSuggestedRooms but uses ctx for the underlying requests.
+func (gitter *Gitter) GetSuggestedRoomsContext(ctx context.Context, userID string) ([]Room, error) {
+
+	var rooms []Room
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/suggestedRooms")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &rooms)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+// GetUserOrgs returns the GitHub organizations of the user
+func (gitter *Gitter) GetUserOrgs(userID string) ([]Org, error) {
+	return gitter.GetUserOrgsContext(context.Background(), userID)
+}
+
+// GetUserOrgsContext is like GetUserOrgs but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserOrgsContext(ctx context.Context, userID string) ([]Org, error) {
+
+	var orgs []Org
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/orgs")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &orgs)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return orgs, nil
+}
+
+// GetUserRepos returns the GitHub repositories of the user
+func (gitter *Gitter) GetUserRepos(userID string) ([]Repo, error) {
+	return gitter.GetUserReposContext(context.Background(), userID)
+}
+
+// GetUserReposContext is like GetUserRepos but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserReposContext(ctx context.Context, userID string) ([]Repo, error) {
+
+	var repos []Repo
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/repos")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &repos)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return repos, nil
+}
+
+// GetUserChannels returns the channels created by the user
+func (gitter *Gitter) GetUserChannels(userID string) ([]Room, error) {
+	return gitter.GetUserChannelsContext(context.Background(), userID)
+}
+
+// GetUserChannelsContext is like GetUserChannels but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserChannelsContext(ctx context.Context, userID string) ([]Room, error) {
+
+	var rooms []Room
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/channels")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &rooms)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return rooms, nil
+}
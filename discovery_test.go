@@ -0,0 +1,140 @@
+package gitter
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetSuggestedRooms(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/suggestedRooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": "xyz",
+                    "uri": "gitterhq/sandbox"
+                }
+            ]
+        `)
+	})
+
+	r, err := gitter.GetSuggestedRooms("abc")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(r) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(r))
+	}
+
+	if r[0].URI != "gitterhq/sandbox" {
+		t.Errorf("Expected %v, got %v", "gitterhq/sandbox", r[0].URI)
+	}
+}
+
+func TestGetUserOrgs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/orgs", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": 42,
+                    "name": "gitterHQ",
+                    "room": {"id": "xyz"}
+                },
+                {
+                    "id": 43,
+                    "name": "noRoom",
+                    "room": null
+                }
+            ]
+        `)
+	})
+
+	o, err := gitter.GetUserOrgs("abc")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(o) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(o))
+	}
+
+	if o[0].Room == nil || o[0].Room.ID != "xyz" {
+		t.Errorf("Expected room %v, got %v", "xyz", o[0].Room)
+	}
+
+	if o[1].Room != nil {
+		t.Errorf("Expected %v, got %v", nil, o[1].Room)
+	}
+}
+
+func TestGetUserRepos(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/repos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": 7,
+                    "name": "sromku/go-gitter",
+                    "uri": "sromku/go-gitter",
+                    "private": false,
+                    "exists": true
+                }
+            ]
+        `)
+	})
+
+	r, err := gitter.GetUserRepos("abc")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(r) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(r))
+	}
+
+	if r[0].Name != "sromku/go-gitter" {
+		t.Errorf("Expected %v, got %v", "sromku/go-gitter", r[0].Name)
+	}
+
+	if !r[0].Exists {
+		t.Errorf("Expected %v, got %v", true, r[0].Exists)
+	}
+}
+
+func TestGetUserChannels(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/channels", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": "xyz",
+                    "githubType": "USER_CHANNEL"
+                }
+            ]
+        `)
+	})
+
+	r, err := gitter.GetUserChannels("abc")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(r) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(r))
+	}
+
+	if r[0].GithubType != "USER_CHANNEL" {
+		t.Errorf("Expected %v, got %v", "USER_CHANNEL", r[0].GithubType)
+	}
+}
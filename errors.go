@@ -0,0 +1,103 @@
+package gitter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Sentinel errors that can be matched against an APIError with errors.Is.
+//
+// For example:
+//
+//	if errors.Is(err, gitter.ErrNotFound) {
+//	    // the room does not exist
+//	}
+var (
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrServer       = errors.New("server error")
+)
+
+// maxErrorBodySize limits how much of a failed response is kept in APIError.
+const maxErrorBodySize = 64 << 10
+
+// APIError holds data of errors returned from the API.
+type APIError struct {
+	What string
+
+	// HTTP status code of the response. Zero if the error did not come
+	// from an HTTP response.
+	StatusCode int
+
+	// Method and URL of the failed request
+	Method string
+	URL    string
+
+	// Error message reported by Gitter in the "error" field of the body
+	Message string
+
+	// Headers of the response
+	Header http.Header
+
+	// Raw response body
+	Body []byte
+}
+
+func newAPIError(r *http.Request, resp *http.Response) APIError {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &payload)
+
+	message := payload.Error
+	if message == "" {
+		message = payload.Message
+	}
+
+	return APIError{
+		What:       fmt.Sprintf("Status code: %v", resp.StatusCode),
+		StatusCode: resp.StatusCode,
+		Method:     r.Method,
+		URL:        r.URL.String(),
+		Message:    message,
+		Header:     resp.Header,
+		Body:       body,
+	}
+}
+
+func (e APIError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("%v", e.What)
+	}
+	msg := fmt.Sprintf("%v %v: %v", e.Method, e.URL, e.What)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is reports whether the error matches one of the sentinel errors.
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
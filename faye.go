@@ -2,7 +2,6 @@ package gitter
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/mrexodia/wray"
 )
@@ -27,7 +26,7 @@ func (gitter *Gitter) Faye(roomID string) *Faye {
 	return &Faye{
 		endpoint: "/api/v1/rooms/" + roomID + "/chatMessages",
 		Event:    make(chan Event),
-		client:   wray.NewFayeClient(fayeBaseURL),
+		client:   wray.NewFayeClient(gitter.config.fayeBaseURL),
 		gitter:   gitter,
 	}
 }
@@ -36,21 +35,21 @@ func (faye *Faye) Listen() {
 	defer faye.destroy()
 
 	faye.client.Subscribe(faye.endpoint, false, func(message wray.Message) {
-		dataBytes, err := json.Marshal(message.Data["model"])
+		dataBytes, err := json.Marshal(message.Data)
 		if err != nil {
-			fmt.Printf("JSON Marshal error: %v\n", err)
+			faye.gitter.log("JSON Marshal error: " + err.Error())
 			return
 		}
-		var gitterMessage Message
-		err = json.Unmarshal(dataBytes, &gitterMessage)
+		data, err := decodeEvent(dataBytes)
 		if err != nil {
-			fmt.Printf("JSON Unmarshal error: %v\n", err)
+			faye.gitter.log("JSON Unmarshal error: " + err.Error())
+			faye.Event <- Event{
+				Data: &StreamError{Err: err},
+			}
 			return
 		}
 		faye.Event <- Event{
-			Data: &MessageReceived{
-				Message: gitterMessage,
-			},
+			Data: data,
 		}
 	})
 
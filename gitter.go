@@ -5,6 +5,7 @@ package gitter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/mreiferson/go-httpclient"
@@ -22,37 +24,73 @@ var (
 	apiBaseURL    = "https://api.gitter.im/v1/"
 	streamBaseURL = "https://stream.gitter.im/v1/"
 	fayeBaseURL   = "https://ws.gitter.im/faye"
+
+	defaultConnectTimeout   = 5 * time.Second
+	defaultReadWriteTimeout = 40 * time.Second
 )
 
 type Gitter struct {
 	config struct {
 		apiBaseURL    string
 		streamBaseURL string
+		fayeBaseURL   string
 		token         string
+		userAgent     string
 		client        *http.Client
+
+		connectTimeout   time.Duration
+		readWriteTimeout time.Duration
+
+		retryPolicy     RetryPolicy
+		rateLimitPolicy RateLimitPolicy
 	}
 	debug     bool
 	logWriter io.Writer
+	logger    *log.Logger
+
+	mu        sync.Mutex
+	rateLimit RateLimit
+	roomIDs   map[string]string
 }
 
 // New initializes the Gitter API client
 //
 // For example:
-//  api := gitter.New("YOUR_ACCESS_TOKEN")
+//
+//	api := gitter.New("YOUR_ACCESS_TOKEN")
 func New(token string) *Gitter {
+	return NewWithOptions(token)
+}
 
-	transport := &httpclient.Transport{
-		ConnectTimeout:   5 * time.Second,
-		ReadWriteTimeout: 40 * time.Second,
-	}
-	defer transport.Close()
+// NewWithOptions initializes the Gitter API client and applies the passed options
+//
+// For example:
+//
+//	api := gitter.NewWithOptions("YOUR_ACCESS_TOKEN",
+//		gitter.WithAPIBaseURL("https://gitter.example.com/api/v1/"),
+//		gitter.WithUserAgent("my-bot/1.0"))
+func NewWithOptions(token string, options ...Option) *Gitter {
 
 	s := &Gitter{}
 	s.config.apiBaseURL = apiBaseURL
 	s.config.streamBaseURL = streamBaseURL
+	s.config.fayeBaseURL = fayeBaseURL
 	s.config.token = token
-	s.config.client = &http.Client{
-		Transport: transport,
+	s.config.connectTimeout = defaultConnectTimeout
+	s.config.readWriteTimeout = defaultReadWriteTimeout
+	s.config.retryPolicy = DefaultRetryPolicy()
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.config.client == nil {
+		s.config.client = &http.Client{
+			Transport: &httpclient.Transport{
+				ConnectTimeout:   s.config.connectTimeout,
+				ReadWriteTimeout: s.config.readWriteTimeout,
+			},
+		}
 	}
 	return s
 }
@@ -64,9 +102,14 @@ func (gitter *Gitter) SetClient(client *http.Client) {
 
 // GetUser returns the current user
 func (gitter *Gitter) GetUser() (*User, error) {
+	return gitter.GetUserContext(context.Background())
+}
+
+// GetUserContext is like GetUser but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserContext(ctx context.Context) (*User, error) {
 
 	var users []User
-	response, err := gitter.get(gitter.config.apiBaseURL + "user")
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user")
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -89,9 +132,14 @@ func (gitter *Gitter) GetUser() (*User, error) {
 
 // GetUserRooms returns a list of Rooms the user is part of
 func (gitter *Gitter) GetUserRooms(userID string) ([]Room, error) {
+	return gitter.GetUserRoomsContext(context.Background(), userID)
+}
+
+// GetUserRoomsContext is like GetUserRooms but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserRoomsContext(ctx context.Context, userID string) ([]Room, error) {
 
 	var rooms []Room
-	response, err := gitter.get(gitter.config.apiBaseURL + "user/" + userID + "/rooms")
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/rooms")
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -108,9 +156,14 @@ func (gitter *Gitter) GetUserRooms(userID string) ([]Room, error) {
 
 // GetRooms returns a list of rooms the current user is in
 func (gitter *Gitter) GetRooms() ([]Room, error) {
+	return gitter.GetRoomsContext(context.Background())
+}
+
+// GetRoomsContext is like GetRooms but uses ctx for the underlying requests.
+func (gitter *Gitter) GetRoomsContext(ctx context.Context) ([]Room, error) {
 
 	var rooms []Room
-	response, err := gitter.get(gitter.config.apiBaseURL + "rooms")
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms")
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -125,10 +178,16 @@ func (gitter *Gitter) GetRooms() ([]Room, error) {
 	return rooms, nil
 }
 
-// GetUsersInRoom returns the users in the room with the passed id
+// GetUsersInRoom returns the users in the room with the passed id.
+// Only the first page is returned, use ListRoomUsers or NewRoomUserIterator for large rooms.
 func (gitter *Gitter) GetUsersInRoom(roomID string) ([]User, error) {
+	return gitter.GetUsersInRoomContext(context.Background(), roomID)
+}
+
+// GetUsersInRoomContext is like GetUsersInRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUsersInRoomContext(ctx context.Context, roomID string) ([]User, error) {
 	var users []User
-	response, err := gitter.get(gitter.config.apiBaseURL + "rooms/" + roomID + "/users")
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/users")
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -144,9 +203,14 @@ func (gitter *Gitter) GetUsersInRoom(roomID string) ([]User, error) {
 
 // GetRoom returns a room with the passed id
 func (gitter *Gitter) GetRoom(roomID string) (*Room, error) {
+	return gitter.GetRoomContext(context.Background(), roomID)
+}
+
+// GetRoomContext is like GetRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) GetRoomContext(ctx context.Context, roomID string) (*Room, error) {
 
 	var room Room
-	response, err := gitter.get(gitter.config.apiBaseURL + "rooms/" + roomID)
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms/"+roomID)
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -164,13 +228,65 @@ func (gitter *Gitter) GetRoom(roomID string) (*Room, error) {
 // GetMessages returns a list of messages in a room.
 // Pagination is optional. You can pass nil or specific pagination params.
 func (gitter *Gitter) GetMessages(roomID string, params *Pagination) ([]Message, error) {
+	return gitter.GetMessagesContext(context.Background(), roomID, params)
+}
+
+// GetMessagesContext is like GetMessages but uses ctx for the underlying requests.
+func (gitter *Gitter) GetMessagesContext(ctx context.Context, roomID string, params *Pagination) ([]Message, error) {
 
 	var messages []Message
 	url := gitter.config.apiBaseURL + "rooms/" + roomID + "/chatMessages"
 	if params != nil {
 		url += "?" + params.encode()
 	}
-	response, err := gitter.get(url)
+	response, err := gitter.get(ctx, url)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &messages)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// SearchMessages returns the messages in a room matching the query.
+// Pagination is optional and may set Lang, Limit and Skip.
+func (gitter *Gitter) SearchMessages(roomID, query string, params *Pagination) ([]Message, error) {
+	return gitter.SearchMessagesContext(context.Background(), roomID, query, params)
+}
+
+// SearchMessagesContext is like SearchMessages but uses ctx for the underlying requests.
+func (gitter *Gitter) SearchMessagesContext(ctx context.Context, roomID, query string, params *Pagination) ([]Message, error) {
+
+	search := Pagination{}
+	if params != nil {
+		search = *params
+	}
+	search.Query = query
+
+	return gitter.GetMessagesContext(ctx, roomID, &search)
+}
+
+// GetThreadMessages returns the messages in the thread of the parent message.
+// Pagination is optional. You can pass nil or specific pagination params.
+func (gitter *Gitter) GetThreadMessages(roomID, parentID string, params *Pagination) ([]Message, error) {
+	return gitter.GetThreadMessagesContext(context.Background(), roomID, parentID, params)
+}
+
+// GetThreadMessagesContext is like GetThreadMessages but uses ctx for the underlying requests.
+func (gitter *Gitter) GetThreadMessagesContext(ctx context.Context, roomID, parentID string, params *Pagination) ([]Message, error) {
+
+	var messages []Message
+	url := gitter.config.apiBaseURL + "rooms/" + roomID + "/chatMessages/" + parentID + "/thread"
+	if params != nil {
+		url += "?" + params.encode()
+	}
+	response, err := gitter.get(ctx, url)
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -187,9 +303,14 @@ func (gitter *Gitter) GetMessages(roomID string, params *Pagination) ([]Message,
 
 // GetMessage returns a message in a room.
 func (gitter *Gitter) GetMessage(roomID, messageID string) (*Message, error) {
+	return gitter.GetMessageContext(context.Background(), roomID, messageID)
+}
+
+// GetMessageContext is like GetMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) GetMessageContext(ctx context.Context, roomID, messageID string) (*Message, error) {
 
 	var message Message
-	response, err := gitter.get(gitter.config.apiBaseURL + "rooms/" + roomID + "/chatMessages/" + messageID)
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/chatMessages/"+messageID)
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -206,15 +327,61 @@ func (gitter *Gitter) GetMessage(roomID, messageID string) (*Message, error) {
 
 // SendMessage sends a message to a room
 func (gitter *Gitter) SendMessage(roomID, text string) (*Message, error) {
+	return gitter.SendMessageWithOptionsContext(context.Background(), roomID, MessageOptions{Text: text})
+}
 
-	message := Message{Text: text}
-	body, _ := json.Marshal(message)
-	response, err := gitter.post(gitter.config.apiBaseURL+"rooms/"+roomID+"/chatMessages", body)
+// SendMessageContext is like SendMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) SendMessageContext(ctx context.Context, roomID, text string) (*Message, error) {
+	return gitter.SendMessageWithOptionsContext(ctx, roomID, MessageOptions{Text: text})
+}
+
+// SendThreadReply sends a message to the thread of the parent message
+func (gitter *Gitter) SendThreadReply(roomID, parentID, text string) (*Message, error) {
+	return gitter.SendMessageWithOptionsContext(context.Background(), roomID, MessageOptions{Text: text, ParentID: parentID})
+}
+
+// SendThreadReplyContext is like SendThreadReply but uses ctx for the underlying requests.
+func (gitter *Gitter) SendThreadReplyContext(ctx context.Context, roomID, parentID, text string) (*Message, error) {
+	return gitter.SendMessageWithOptionsContext(ctx, roomID, MessageOptions{Text: text, ParentID: parentID})
+}
+
+// MessageOptions holds the content and formatting of a message to send
+type MessageOptions struct {
+
+	// Message in plain-text/markdown
+	Text string `json:"text"`
+
+	// Send the message as a status (/me) message
+	Status bool `json:"status,omitempty"`
+
+	// ID of the parent message, to reply in its thread
+	ParentID string `json:"parentId,omitempty"`
+}
+
+// SendMessageWithOptions sends a message to a room, optionally as a status
+// message or thread reply
+//
+// For example:
+//
+//	message, err := api.SendMessageWithOptions("roomID", gitter.MessageOptions{
+//		Text:   "is deploying",
+//		Status: true,
+//	})
+func (gitter *Gitter) SendMessageWithOptions(roomID string, options MessageOptions) (*Message, error) {
+	return gitter.SendMessageWithOptionsContext(context.Background(), roomID, options)
+}
+
+// SendMessageWithOptionsContext is like SendMessageWithOptions but uses ctx for the underlying requests.
+func (gitter *Gitter) SendMessageWithOptionsContext(ctx context.Context, roomID string, options MessageOptions) (*Message, error) {
+
+	body, _ := json.Marshal(options)
+	response, err := gitter.post(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/chatMessages", body)
 	if err != nil {
 		gitter.log(err)
 		return nil, err
 	}
 
+	var message Message
 	err = json.Unmarshal(response, &message)
 	if err != nil {
 		gitter.log(err)
@@ -226,10 +393,15 @@ func (gitter *Gitter) SendMessage(roomID, text string) (*Message, error) {
 
 // UpdateMessage updates a message in a room
 func (gitter *Gitter) UpdateMessage(roomID, msgID, text string) (*Message, error) {
+	return gitter.UpdateMessageContext(context.Background(), roomID, msgID, text)
+}
+
+// UpdateMessageContext is like UpdateMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) UpdateMessageContext(ctx context.Context, roomID, msgID, text string) (*Message, error) {
 
 	message := Message{Text: text}
 	body, _ := json.Marshal(message)
-	response, err := gitter.put(gitter.config.apiBaseURL+"rooms/"+roomID+"/chatMessages/"+msgID, body)
+	response, err := gitter.put(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/chatMessages/"+msgID, body)
 	if err != nil {
 		gitter.log(err)
 		return nil, err
@@ -244,12 +416,34 @@ func (gitter *Gitter) UpdateMessage(roomID, msgID, text string) (*Message, error
 	return &message, nil
 }
 
+// DeleteMessage deletes a message in a room
+func (gitter *Gitter) DeleteMessage(roomID, msgID string) error {
+	return gitter.DeleteMessageContext(context.Background(), roomID, msgID)
+}
+
+// DeleteMessageContext is like DeleteMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) DeleteMessageContext(ctx context.Context, roomID, msgID string) error {
+
+	_, err := gitter.delete(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/chatMessages/"+msgID)
+	if err != nil {
+		gitter.log(err)
+		return err
+	}
+
+	return nil
+}
+
 // JoinRoom joins a room
 func (gitter *Gitter) JoinRoom(roomID, userID string) (*Room, error) {
+	return gitter.JoinRoomContext(context.Background(), roomID, userID)
+}
+
+// JoinRoomContext is like JoinRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) JoinRoomContext(ctx context.Context, roomID, userID string) (*Room, error) {
 
 	message := Room{ID: roomID}
 	body, _ := json.Marshal(message)
-	response, err := gitter.post(gitter.config.apiBaseURL+"user/"+userID+"/rooms", body)
+	response, err := gitter.post(ctx, gitter.config.apiBaseURL+"user/"+userID+"/rooms", body)
 
 	if err != nil {
 		gitter.log(err)
@@ -268,8 +462,13 @@ func (gitter *Gitter) JoinRoom(roomID, userID string) (*Room, error) {
 
 // LeaveRoom removes a user from the room
 func (gitter *Gitter) LeaveRoom(roomID, userID string) error {
+	return gitter.LeaveRoomContext(context.Background(), roomID, userID)
+}
+
+// LeaveRoomContext is like LeaveRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) LeaveRoomContext(ctx context.Context, roomID, userID string) error {
 
-	_, err := gitter.delete(gitter.config.apiBaseURL + "rooms/" + roomID + "/users/" + userID)
+	_, err := gitter.delete(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/users/"+userID)
 	if err != nil {
 		gitter.log(err)
 		return err
@@ -286,12 +485,17 @@ func (gitter *Gitter) SetDebug(debug bool, logWriter io.Writer) {
 
 // SearchRooms queries the Rooms resources of gitter API
 func (gitter *Gitter) SearchRooms(room string) ([]Room, error) {
+	return gitter.SearchRoomsContext(context.Background(), room)
+}
+
+// SearchRoomsContext is like SearchRooms but uses ctx for the underlying requests.
+func (gitter *Gitter) SearchRoomsContext(ctx context.Context, room string) ([]Room, error) {
 
 	var rooms struct {
 		Results []Room `json:"results"`
 	}
 
-	response, err := gitter.get(gitter.config.apiBaseURL + "rooms?q=" + room)
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms?q="+room)
 
 	if err != nil {
 		gitter.log(err)
@@ -306,21 +510,27 @@ func (gitter *Gitter) SearchRooms(room string) ([]Room, error) {
 	return rooms.Results, nil
 }
 
-// GetRoomId returns the room ID of a given URI
+// GetRoomId returns the room ID of a given URI.
+// The first lookup of a URI joins the room, like JoinRoomByURI, which also
+// works for private rooms. Later lookups are answered from the local cache.
 func (gitter *Gitter) GetRoomId(uri string) (string, error) {
+	return gitter.GetRoomIdContext(context.Background(), uri)
+}
+
+// GetRoomIdContext is like GetRoomId but uses ctx for the underlying requests.
+func (gitter *Gitter) GetRoomIdContext(ctx context.Context, uri string) (string, error) {
 
-	rooms, err := gitter.SearchRooms(uri)
+	if roomID, ok := gitter.cachedRoomID(uri); ok {
+		return roomID, nil
+	}
+
+	room, err := gitter.JoinRoomByURIContext(ctx, uri)
 	if err != nil {
 		gitter.log(err)
 		return "", err
 	}
 
-	for _, element := range rooms {
-		if element.URI == uri {
-			return element.ID, nil
-		}
-	}
-	return "", APIError{What: "Room not found."}
+	return room.ID, nil
 }
 
 // Pagination params
@@ -340,6 +550,9 @@ type Pagination struct {
 
 	// Search query
 	Query string
+
+	// Language of the search query, e.g. "en"
+	Lang string
 }
 
 func (messageParams *Pagination) encode() string {
@@ -361,21 +574,23 @@ func (messageParams *Pagination) encode() string {
 		values.Add("limit", strconv.Itoa(messageParams.Limit))
 	}
 
+	if messageParams.Query != "" {
+		values.Add("q", messageParams.Query)
+	}
+
+	if messageParams.Lang != "" {
+		values.Add("lang", messageParams.Lang)
+	}
+
 	return values.Encode()
 }
 
-func (gitter *Gitter) getResponse(url string, stream *Stream) (*http.Response, error) {
-	r, err := http.NewRequest("GET", url, nil)
+func (gitter *Gitter) getResponse(ctx context.Context, url string) (*http.Response, error) {
+	r, err := gitter.newRequest(ctx, "GET", url, nil)
 	if err != nil {
 		gitter.log(err)
 		return nil, err
 	}
-	r.Header.Set("Content-Type", "application/json")
-	r.Header.Set("Accept", "application/json")
-	r.Header.Set("Authorization", "Bearer "+gitter.config.token)
-	if stream != nil {
-		stream.streamConnection.request = r
-	}
 	response, err := gitter.config.client.Do(r)
 	if err != nil {
 		gitter.log(err)
@@ -384,73 +599,76 @@ func (gitter *Gitter) getResponse(url string, stream *Stream) (*http.Response, e
 	return response, nil
 }
 
-func (gitter *Gitter) get(url string) ([]byte, error) {
-	resp, err := gitter.getResponse(url, nil)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		err = APIError{What: fmt.Sprintf("Status code: %v", resp.StatusCode)}
-		gitter.log(err)
-		return nil, err
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
+func (gitter *Gitter) newRequest(ctx context.Context, method, url string, body []byte) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
 	}
-
-	return body, nil
-}
-
-func (gitter *Gitter) post(url string, body []byte) ([]byte, error) {
-	r, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	r, err := http.NewRequest(method, url, reader)
 	if err != nil {
-		gitter.log(err)
 		return nil, err
 	}
-
+	r = r.WithContext(ctx)
 	r.Header.Set("Content-Type", "application/json")
 	r.Header.Set("Accept", "application/json")
 	r.Header.Set("Authorization", "Bearer "+gitter.config.token)
-
-	resp, err := gitter.config.client.Do(r)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
+	if gitter.config.userAgent != "" {
+		r.Header.Set("User-Agent", gitter.config.userAgent)
 	}
-	defer resp.Body.Close()
+	return r, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		err = APIError{What: fmt.Sprintf("Status code: %v", resp.StatusCode)}
-		gitter.log(err)
-		return nil, err
-	}
+// do sends a request and returns the body of a successful response.
+// It is the shared path used by get, post, put and delete, and applies the
+// rate limit and retry policies.
+func (gitter *Gitter) do(ctx context.Context, method, url string, body []byte) ([]byte, error) {
+	gitter.mu.Lock()
+	retryPolicy := gitter.config.retryPolicy
+	rateLimitPolicy := gitter.config.rateLimitPolicy
+	gitter.mu.Unlock()
+
+	attempt := 0
+	rateLimitRetries := 0
+	for {
+		if err := gitter.waitRateLimit(ctx, rateLimitPolicy, method, url); err != nil {
+			gitter.log(err)
+			return nil, err
+		}
 
-	result, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
-	}
+		// a request that can't be built fails the same way on every attempt
+		r, err := gitter.newRequest(ctx, method, url, body)
+		if err != nil {
+			gitter.log(err)
+			return nil, err
+		}
 
-	return result, nil
-}
+		attempt++
+		result, err := gitter.send(r)
+
+		var delay time.Duration
+		if rateLimitDelay, ok := gitter.rateLimitDelay(rateLimitPolicy, err, rateLimitRetries); ok {
+			// waiting for the rate limit does not count as an attempt,
+			// it is capped by the rate limit policy instead
+			attempt--
+			rateLimitRetries++
+			delay = rateLimitDelay
+			gitter.log("Rate limited, retrying in " + delay.String())
+		} else if retryPolicy.shouldRetry(ctx, method, attempt, err) {
+			delay = retryPolicy.delay(attempt)
+			gitter.log(fmt.Sprintf("Attempt %v failed, retrying in %v", attempt, delay))
+		} else {
+			return result, err
+		}
 
-func (gitter *Gitter) put(url string, body []byte) ([]byte, error) {
-	r, err := http.NewRequest("PUT", url, bytes.NewBuffer(body))
-	if err != nil {
-		gitter.log(err)
-		return nil, err
+		if err := sleep(ctx, delay); err != nil {
+			gitter.log(err)
+			return nil, err
+		}
 	}
+}
 
-	r.Header.Set("Content-Type", "application/json")
-	r.Header.Set("Accept", "application/json")
-	r.Header.Set("Authorization", "Bearer "+gitter.config.token)
-
+// send performs a single attempt of a request.
+func (gitter *Gitter) send(r *http.Request) ([]byte, error) {
 	resp, err := gitter.config.client.Do(r)
 	if err != nil {
 		gitter.log(err)
@@ -458,8 +676,10 @@ func (gitter *Gitter) put(url string, body []byte) ([]byte, error) {
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		err = APIError{What: fmt.Sprintf("Status code: %v", resp.StatusCode)}
+	gitter.updateRateLimit(resp.Header)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = newAPIError(r, resp)
 		gitter.log(err)
 		return nil, err
 	}
@@ -473,42 +693,29 @@ func (gitter *Gitter) put(url string, body []byte) ([]byte, error) {
 	return result, nil
 }
 
-func (gitter *Gitter) delete(url string) ([]byte, error) {
-	r, err := http.NewRequest("delete", url, nil)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
-	}
-
-	r.Header.Set("Content-Type", "application/json")
-	r.Header.Set("Accept", "application/json")
-	r.Header.Set("Authorization", "Bearer "+gitter.config.token)
-
-	resp, err := gitter.config.client.Do(r)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
-	}
-	defer resp.Body.Close()
+func (gitter *Gitter) get(ctx context.Context, url string) ([]byte, error) {
+	return gitter.do(ctx, "GET", url, nil)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		err = APIError{What: fmt.Sprintf("Status code: %v", resp.StatusCode)}
-		gitter.log(err)
-		return nil, err
-	}
+func (gitter *Gitter) post(ctx context.Context, url string, body []byte) ([]byte, error) {
+	return gitter.do(ctx, "POST", url, body)
+}
 
-	result, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		gitter.log(err)
-		return nil, err
-	}
+func (gitter *Gitter) put(ctx context.Context, url string, body []byte) ([]byte, error) {
+	return gitter.do(ctx, "PUT", url, body)
+}
 
-	return result, nil
+func (gitter *Gitter) delete(ctx context.Context, url string) ([]byte, error) {
+	return gitter.do(ctx, "DELETE", url, nil)
 }
 
 func (gitter *Gitter) log(a interface{}) {
 	if gitter.debug {
-		log.Println(a)
+		if gitter.logger != nil {
+			gitter.logger.Println(a)
+		} else {
+			log.Println(a)
+		}
 		if gitter.logWriter != nil {
 			timestamp := time.Now().Format(time.RFC3339)
 			msg := fmt.Sprintf("%v: %v", timestamp, a)
@@ -516,12 +723,3 @@ func (gitter *Gitter) log(a interface{}) {
 		}
 	}
 }
-
-// APIError holds data of errors returned from the API.
-type APIError struct {
-	What string
-}
-
-func (e APIError) Error() string {
-	return fmt.Sprintf("%v", e.What)
-}
@@ -1,9 +1,17 @@
 package gitter
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNew_setToken(t *testing.T) {
@@ -255,6 +263,60 @@ func TestGetMessages_limit(t *testing.T) {
 	}
 }
 
+func TestSearchMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("q") != "hello world" {
+			t.Errorf("Expected %v, got %v", "hello world", query.Get("q"))
+		}
+
+		if query.Get("lang") != "en" {
+			t.Errorf("Expected %v, got %v", "en", query.Get("lang"))
+		}
+
+		if query.Get("limit") != "10" {
+			t.Errorf("Expected %v, got %v", "10", query.Get("limit"))
+		}
+
+		if query.Get("skip") != "5" {
+			t.Errorf("Expected %v, got %v", "5", query.Get("skip"))
+		}
+
+		fmt.Fprint(w, `
+            [{
+                "id": "666",
+                "text": "hello world"
+            }]
+        `)
+	})
+
+	p := &Pagination{
+		Lang:  "en",
+		Limit: 10,
+		Skip:  5,
+	}
+
+	m, err := gitter.SearchMessages("xyz", "hello world", p)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(m) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(m))
+	}
+
+	if m[0].Text != "hello world" {
+		t.Errorf("Expected %v, got %v", "hello world", m[0].Text)
+	}
+
+	if p.Query != "" {
+		t.Errorf("Expected %v, got %v", "", p.Query)
+	}
+}
+
 func TestGetMessage(t *testing.T) {
 	setup()
 	defer teardown()
@@ -282,10 +344,153 @@ func TestSendMessage(t *testing.T) {
 	defer teardown()
 
 	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `
+            {
+                "id": "666"
+            }
+        `)
 	})
 
-	err := gitter.SendMessage("xyz", "test message.")
+	_, err := gitter.SendMessage("xyz", "test message.")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestSendMessageWithOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["text"] != "is deploying" {
+			t.Errorf("Expected %v, got %v", "is deploying", payload["text"])
+		}
+
+		if payload["status"] != true {
+			t.Errorf("Expected %v, got %v", true, payload["status"])
+		}
+
+		if payload["parentId"] != "666" {
+			t.Errorf("Expected %v, got %v", "666", payload["parentId"])
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "667",
+                "text": "is deploying",
+                "status": true,
+                "parentId": "666"
+            }
+        `)
+	})
+
+	m, err := gitter.SendMessageWithOptions("xyz", MessageOptions{
+		Text:     "is deploying",
+		Status:   true,
+		ParentID: "666",
+	})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if !m.Status {
+		t.Errorf("Expected %v, got %v", true, m.Status)
+	}
+
+	if m.ParentID != "666" {
+		t.Errorf("Expected %v, got %v", "666", m.ParentID)
+	}
+}
+
+func TestSendMessage_plainPayload(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if len(payload) != 1 {
+			t.Errorf("Expected %v, got %v", 1, payload)
+		}
+
+		fmt.Fprint(w, `{"id": "666"}`)
+	})
+
+	_, err := gitter.SendMessage("xyz", "test message.")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestSendThreadReply(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		var m Message
+		json.NewDecoder(r.Body).Decode(&m)
+		if m.ParentID != "666" {
+			t.Errorf("Expected %v, got %v", "666", m.ParentID)
+		}
+		fmt.Fprint(w, `
+            {
+                "id": "667",
+                "parentId": "666"
+            }
+        `)
+	})
+
+	m, err := gitter.SendThreadReply("xyz", "666", "reply")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if m.ParentID != "666" {
+		t.Errorf("Expected %v, got %v", "666", m.ParentID)
+	}
+}
+
+func TestGetThreadMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages/666/thread", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [{
+                "id": "667",
+                "parentId": "666"
+            }]
+        `)
+	})
+
+	m, err := gitter.GetThreadMessages("xyz", "666", nil)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(m) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(m))
+	}
+
+	if m[0].ParentID != "666" {
+		t.Errorf("Expected %v, got %v", "666", m[0].ParentID)
+	}
+}
+
+func TestDeleteMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages/666", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected %v, got %v", "DELETE", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	err := gitter.DeleteMessage("xyz", "666")
 	if err != nil {
 		t.Errorf("Expected %v, got %v", nil, err)
 	}
@@ -295,7 +500,7 @@ func TestGetResponse(t *testing.T) {
 	setup()
 	defer teardown()
 
-	r, err := gitter.getResponse(gitter.config.apiBaseURL, nil)
+	r, err := gitter.getResponse(context.Background(), gitter.config.apiBaseURL)
 	if err != nil {
 		t.Errorf("Expected %v, got %v", nil, err)
 	}
@@ -325,7 +530,7 @@ func TestGet_endpoint(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	_, err := gitter.get(gitter.config.apiBaseURL)
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
 	if err != nil {
 		t.Errorf("Expected %v, got %v", nil, err)
 	}
@@ -339,7 +544,7 @@ func TestGet_error(t *testing.T) {
 		w.WriteHeader(http.StatusBadGateway)
 	})
 
-	b, _ := gitter.get(gitter.config.apiBaseURL)
+	b, _ := gitter.get(context.Background(), gitter.config.apiBaseURL)
 	if b != nil {
 		t.Errorf("Expected %v, got %v", nil, b)
 	}
@@ -365,8 +570,495 @@ func TestPost(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	_, err := gitter.post(gitter.config.apiBaseURL, []byte{})
+	_, err := gitter.post(context.Background(), gitter.config.apiBaseURL, []byte{})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestGetMessagesContext_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m, err := gitter.GetMessagesContext(ctx, "xyz", nil)
+	if m != nil {
+		t.Errorf("Expected %v, got %v", nil, m)
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestDelete_method(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected %v, got %v", "DELETE", r.Method)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	_, err := gitter.delete(context.Background(), gitter.config.apiBaseURL)
 	if err != nil {
 		t.Errorf("Expected %v, got %v", nil, err)
 	}
 }
+
+func TestGet_apiError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Custom", "value")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error": "Not Found"}`)
+	})
+
+	_, err := gitter.GetRoom("xyz")
+
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected %T, got %v", apiErr, err)
+	}
+
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected %v, got %v", http.StatusNotFound, apiErr.StatusCode)
+	}
+
+	if apiErr.Method != "GET" {
+		t.Errorf("Expected %v, got %v", "GET", apiErr.Method)
+	}
+
+	if apiErr.URL != gitter.config.apiBaseURL+"rooms/xyz" {
+		t.Errorf("Expected %v, got %v", gitter.config.apiBaseURL+"rooms/xyz", apiErr.URL)
+	}
+
+	if apiErr.Message != "Not Found" {
+		t.Errorf("Expected %v, got %v", "Not Found", apiErr.Message)
+	}
+
+	if apiErr.Header.Get("X-Custom") != "value" {
+		t.Errorf("Expected %v, got %v", "value", apiErr.Header.Get("X-Custom"))
+	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected %v, got %v", ErrNotFound, err)
+	}
+
+	if errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected %v not to match %v", err, ErrUnauthorized)
+	}
+}
+
+func TestAPIError_is(t *testing.T) {
+	tests := []struct {
+		code   int
+		target error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadGateway, ErrServer},
+	}
+
+	for _, test := range tests {
+		err := APIError{StatusCode: test.code}
+		if !errors.Is(err, test.target) {
+			t.Errorf("Expected %v to match %v", test.code, test.target)
+		}
+	}
+}
+
+func TestGet_rateLimitHeaders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "1500000000000")
+		w.WriteHeader(http.StatusOK)
+	})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	rl := gitter.RateLimit()
+	if rl.Limit != 100 {
+		t.Errorf("Expected %v, got %v", 100, rl.Limit)
+	}
+
+	if rl.Remaining != 42 {
+		t.Errorf("Expected %v, got %v", 42, rl.Remaining)
+	}
+
+	if !rl.Reset.Equal(time.Unix(1500000000, 0)) {
+		t.Errorf("Expected %v, got %v", time.Unix(1500000000, 0), rl.Reset)
+	}
+}
+
+func TestGet_rateLimited(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected %v, got %v", ErrRateLimited, err)
+	}
+}
+
+func TestGet_rateLimitWait(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			reset := time.Now().Add(20*time.Millisecond).UnixNano() / int64(time.Millisecond)
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	gitter.SetRateLimitPolicy(RateLimitPolicy{Wait: true})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected %v, got %v", 2, n)
+	}
+}
+
+func TestGet_rateLimitWaitCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	gitter.SetRateLimitPolicy(RateLimitPolicy{Wait: true})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := gitter.get(ctx, gitter.config.apiBaseURL)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestGet_rateLimitMaxRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	gitter.SetRateLimitPolicy(RateLimitPolicy{Wait: true, MaxRetries: 2})
+
+	start := time.Now()
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected %v, got %v", ErrRateLimited, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("Expected %v, got %v", 3, n)
+	}
+
+	if elapsed := time.Since(start); elapsed < 2*minRateLimitDelay {
+		t.Errorf("Expected at least %v, got %v", 2*minRateLimitDelay, elapsed)
+	}
+}
+
+func TestGet_rateLimitMaxWait(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		reset := time.Now().Add(time.Hour).Unix()
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(http.StatusOK)
+	})
+
+	gitter.SetRateLimitPolicy(RateLimitPolicy{Wait: true, MaxWait: time.Second})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	_, err = gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected %v, got %v", ErrRateLimited, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+}
+
+func TestSetRateLimitPolicy_concurrent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			gitter.SetRateLimitPolicy(RateLimitPolicy{Wait: true})
+		}()
+		go func() {
+			defer wg.Done()
+			gitter.get(context.Background(), gitter.config.apiBaseURL)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGet_retryServerError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("Expected %v, got %v", 3, n)
+	}
+}
+
+func TestGet_retryMaxAttempts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("Expected %v, got %v", ErrServer, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Errorf("Expected %v, got %v", 3, n)
+	}
+}
+
+func TestSetRetryPolicy_concurrent(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			gitter.SetRetryPolicy(RetryPolicy{})
+		}()
+		go func() {
+			defer wg.Done()
+			gitter.get(context.Background(), gitter.config.apiBaseURL)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGet_retryConnectionError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected %v, got %v", 2, n)
+	}
+}
+
+func TestGet_noRetryReadError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Content-Length", "100")
+		fmt.Fprint(w, "{}")
+	})
+
+	_, err := gitter.get(context.Background(), gitter.config.apiBaseURL)
+	if err == nil {
+		t.Errorf("Expected error, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+}
+
+func TestGet_noRetryInvalidURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Hour
+	gitter.SetRetryPolicy(policy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms/%zz")
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected invalid URL error, got %v", err)
+	}
+}
+
+func TestPost_noRetryByDefault(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := gitter.post(context.Background(), gitter.config.apiBaseURL, []byte("{}"))
+	if err == nil {
+		t.Errorf("Expected error, got %v", err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+}
+
+func TestPost_retryOptIn(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if string(body) != "{}" {
+			t.Errorf("Expected %v, got %v", "{}", string(body))
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.Methods = append(policy.Methods, "POST")
+	gitter.SetRetryPolicy(policy)
+
+	_, err := gitter.post(context.Background(), gitter.config.apiBaseURL, []byte("{}"))
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("Expected %v, got %v", 2, n)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := 100 * time.Millisecond
+	max := time.Second
+
+	tests := []struct {
+		retry int
+		want  time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second},
+	}
+
+	for _, test := range tests {
+		if d := backoff(base, max, 0, test.retry); d != test.want {
+			t.Errorf("Expected %v, got %v", test.want, d)
+		}
+	}
+
+	for i := 0; i < 100; i++ {
+		d := backoff(base, max, 0.5, 1)
+		if d < 50*time.Millisecond || d > base {
+			t.Errorf("Expected delay between %v and %v, got %v", 50*time.Millisecond, base, d)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetGroups returns the groups (communities) the current user is a member of
+func (gitter *Gitter) GetGroups() ([]Group, error) {
+	return gitter.GetGroupsContext(context.Background())
+}
+
+// GetGroupsContext is like GetGroups but uses ctx for the underlying requests.
+func (gitter *Gitter) GetGroupsContext(ctx context.Context) ([]Group, error) {
+
+	var groups []Group
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"groups")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &groups)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// GetGroup returns a group with the passed id
+func (gitter *Gitter) GetGroup(groupID string) (*Group, error) {
+	return gitter.GetGroupContext(context.Background(), groupID)
+}
+
+// GetGroupContext is like GetGroup but uses ctx for the underlying requests.
+func (gitter *Gitter) GetGroupContext(ctx context.Context, groupID string) (*Group, error) {
+
+	var group Group
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"groups/"+groupID)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &group)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// GetGroupRooms returns the rooms of a group
+func (gitter *Gitter) GetGroupRooms(groupID string) ([]Room, error) {
+	return gitter.GetGroupRoomsContext(context.Background(), groupID)
+}
+
+// GetGroupRoomsContext is like GetGroupRooms but uses ctx for the underlying requests.
+func (gitter *Gitter) GetGroupRoomsContext(ctx context.Context, groupID string) ([]Room, error) {
+
+	var rooms []Room
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"groups/"+groupID+"/rooms")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &rooms)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return rooms, nil
+}
+
+// CreateGroupRoom creates a new room in a group
+func (gitter *Gitter) CreateGroupRoom(groupID string, room RoomCreate) (*Room, error) {
+	return gitter.CreateGroupRoomContext(context.Background(), groupID, room)
+}
+
+// CreateGroupRoomContext is like CreateGroupRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) CreateGroupRoomContext(ctx context.Context, groupID string, room RoomCreate) (*Room, error) {
+
+	// Group rooms take the security settings as a nested object
+	payload := struct {
+		Name     string `json:"name"`
+		Topic    string `json:"topic,omitempty"`
+		Security struct {
+			Security string `json:"security,omitempty"`
+		} `json:"security"`
+	}{
+		Name:  room.Name,
+		Topic: room.Topic,
+	}
+	payload.Security.Security = room.Security
+	body, _ := json.Marshal(payload)
+	response, err := gitter.post(ctx, gitter.config.apiBaseURL+"groups/"+groupID+"/rooms", body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var created Room
+	err = json.Unmarshal(response, &created)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &created, nil
+}
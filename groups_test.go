@@ -0,0 +1,145 @@
+package gitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetGroups(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/groups", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": "g1",
+                    "name": "Gitter",
+                    "uri": "gitterHQ",
+                    "backedBy": {
+                        "type": "GH_ORG",
+                        "linkPath": "gitterHQ"
+                    }
+                }
+            ]
+        `)
+	})
+
+	g, err := gitter.GetGroups()
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(g) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(g))
+	}
+
+	if g[0].ID != "g1" {
+		t.Errorf("Expected %v, got %v", "g1", g[0].ID)
+	}
+
+	if g[0].BackedBy.Type != "GH_ORG" {
+		t.Errorf("Expected %v, got %v", "GH_ORG", g[0].BackedBy.Type)
+	}
+}
+
+func TestGetGroup(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/groups/g1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "id": "g1",
+                "uri": "gitterHQ"
+            }
+        `)
+	})
+
+	g, err := gitter.GetGroup("g1")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if g.URI != "gitterHQ" {
+		t.Errorf("Expected %v, got %v", "gitterHQ", g.URI)
+	}
+}
+
+func TestGetGroupRooms(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/groups/g1/rooms", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": "xyz",
+                    "groupId": "g1"
+                },
+                {
+                    "id": "cde",
+                    "groupId": "g1"
+                }
+            ]
+        `)
+	})
+
+	r, err := gitter.GetGroupRooms("g1")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(r) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(r))
+	}
+
+	if r[1].GroupID != "g1" {
+		t.Errorf("Expected %v, got %v", "g1", r[1].GroupID)
+	}
+}
+
+func TestCreateGroupRoom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/groups/g1/rooms", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected %v, got %v", "POST", r.Method)
+		}
+
+		var payload struct {
+			Name     string `json:"name"`
+			Security struct {
+				Security string `json:"security"`
+			} `json:"security"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Name != "general" {
+			t.Errorf("Expected %v, got %v", "general", payload.Name)
+		}
+
+		if payload.Security.Security != SecurityPublic {
+			t.Errorf("Expected %v, got %v", SecurityPublic, payload.Security.Security)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "name": "general",
+                "groupId": "g1"
+            }
+        `)
+	})
+
+	r, err := gitter.CreateGroupRoom("g1", RoomCreate{Name: "general", Security: SecurityPublic})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if r.ID != "xyz" {
+		t.Errorf("Expected %v, got %v", "xyz", r.ID)
+	}
+}
@@ -0,0 +1,289 @@
+package gitter
+
+import "context"
+
+// Direction in which a MessageIterator walks through the history of a room.
+type Direction int
+
+const (
+	// Backward walks from newer to older messages.
+	Backward Direction = iota
+
+	// Forward walks from older to newer messages.
+	Forward
+)
+
+// defaultPageSize is the largest page Gitter returns for chat messages.
+const defaultPageSize = 100
+
+// MessageIteratorOptions configures a MessageIterator.
+type MessageIteratorOptions struct {
+
+	// Direction of the walk. Defaults to Backward.
+	Direction Direction
+
+	// Message ID to start from, exclusive. Empty starts at the latest
+	// messages of the room when walking Backward and at the oldest when
+	// walking Forward. Finding the oldest message takes a backward walk
+	// through the whole history first.
+	StartID string
+
+	// Number of messages fetched per request. Defaults to 100.
+	PageSize int
+}
+
+// MessageIterator walks through the history of a room, one page at a time.
+//
+// For example:
+//
+//	it := api.NewMessageIterator("roomID", nil)
+//	for it.Next() {
+//		fmt.Println(it.Message().Text)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type MessageIterator struct {
+	gitter  *Gitter
+	ctx     context.Context
+	roomID  string
+	options MessageIteratorOptions
+
+	cursor   string
+	page     []Message
+	lastPage map[string]bool
+	message  Message
+	done     bool
+	err      error
+}
+
+// NewMessageIterator returns an iterator over the messages of a room.
+// Options are optional. You can pass nil or specific options.
+func (gitter *Gitter) NewMessageIterator(roomID string, options *MessageIteratorOptions) *MessageIterator {
+	return gitter.NewMessageIteratorContext(context.Background(), roomID, options)
+}
+
+// NewMessageIteratorContext is like NewMessageIterator but uses ctx for the underlying requests.
+// The iteration stops with ctx.Err() once ctx is done.
+func (gitter *Gitter) NewMessageIteratorContext(ctx context.Context, roomID string, options *MessageIteratorOptions) *MessageIterator {
+	it := &MessageIterator{
+		gitter: gitter,
+		ctx:    ctx,
+		roomID: roomID,
+	}
+	if options != nil {
+		it.options = *options
+	}
+	if it.options.PageSize <= 0 {
+		it.options.PageSize = defaultPageSize
+	}
+	it.cursor = it.options.StartID
+	return it
+}
+
+// Next advances the iterator to the next message. It returns false when the
+// history is exhausted or an error occurred.
+func (it *MessageIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		it.fetch()
+	}
+
+	it.message = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Message returns the current message.
+func (it *MessageIterator) Message() Message {
+	return it.message
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *MessageIterator) Err() error {
+	return it.err
+}
+
+func (it *MessageIterator) fetch() {
+	if it.options.Direction == Forward && it.cursor == "" {
+		it.fetchOldest()
+		return
+	}
+
+	params := &Pagination{Limit: it.options.PageSize}
+	if it.options.Direction == Forward {
+		params.AfterID = it.cursor
+	} else {
+		params.BeforeID = it.cursor
+	}
+
+	messages, err := it.gitter.GetMessagesContext(it.ctx, it.roomID, params)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	// Drop messages that were already returned with the previous page,
+	// which can happen when new messages shift the page boundaries.
+	var fresh []Message
+	page := make(map[string]bool, len(messages))
+	for _, message := range messages {
+		page[message.ID] = true
+		if !it.lastPage[message.ID] {
+			fresh = append(fresh, message)
+		}
+	}
+	it.lastPage = page
+
+	if len(fresh) == 0 {
+		it.done = true
+		return
+	}
+
+	// Gitter returns every page in chronological order.
+	if it.options.Direction == Forward {
+		it.cursor = messages[len(messages)-1].ID
+	} else {
+		it.cursor = messages[0].ID
+		for i, j := 0, len(fresh)-1; i < j; i, j = i+1, j-1 {
+			fresh[i], fresh[j] = fresh[j], fresh[i]
+		}
+	}
+	it.page = fresh
+}
+
+// fetchOldest loads the first page of the history. Without afterId Gitter
+// returns the latest messages, so it walks back until no older ones are left.
+func (it *MessageIterator) fetchOldest() {
+	var oldest []Message
+	for {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return
+		}
+
+		params := &Pagination{Limit: it.options.PageSize}
+		if len(oldest) > 0 {
+			params.BeforeID = oldest[0].ID
+		}
+		messages, err := it.gitter.GetMessagesContext(it.ctx, it.roomID, params)
+		if err != nil {
+			it.err = err
+			return
+		}
+		if len(messages) == 0 || (len(oldest) > 0 && messages[0].ID == oldest[0].ID) {
+			break
+		}
+		oldest = messages
+	}
+
+	if len(oldest) == 0 {
+		it.done = true
+		return
+	}
+
+	it.lastPage = make(map[string]bool, len(oldest))
+	for _, message := range oldest {
+		it.lastPage[message.ID] = true
+	}
+	it.cursor = oldest[len(oldest)-1].ID
+	it.page = oldest
+}
+
+// RoomUserIterator walks through all users of a room, one page at a time.
+//
+// For example:
+//
+//	it := api.NewRoomUserIterator("roomID", nil)
+//	for it.Next() {
+//		fmt.Println(it.User().Username)
+//	}
+//	if err := it.Err(); err != nil {
+//		// handle error
+//	}
+type RoomUserIterator struct {
+	gitter  *Gitter
+	ctx     context.Context
+	roomID  string
+	options RoomUsersOptions
+
+	page []User
+	user User
+	done bool
+	err  error
+}
+
+// NewRoomUserIterator returns an iterator over the users of a room.
+// Options are optional. Query and Skip are honored and Limit sets the page size.
+func (gitter *Gitter) NewRoomUserIterator(roomID string, options *RoomUsersOptions) *RoomUserIterator {
+	return gitter.NewRoomUserIteratorContext(context.Background(), roomID, options)
+}
+
+// NewRoomUserIteratorContext is like NewRoomUserIterator but uses ctx for the underlying requests.
+// The iteration stops with ctx.Err() once ctx is done.
+func (gitter *Gitter) NewRoomUserIteratorContext(ctx context.Context, roomID string, options *RoomUsersOptions) *RoomUserIterator {
+	it := &RoomUserIterator{
+		gitter: gitter,
+		ctx:    ctx,
+		roomID: roomID,
+	}
+	if options != nil {
+		it.options = *options
+	}
+	if it.options.Limit <= 0 {
+		it.options.Limit = defaultPageSize
+	}
+	return it
+}
+
+// Next advances the iterator to the next user. It returns false when all
+// users were returned or an error occurred.
+func (it *RoomUserIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+		it.fetch()
+	}
+
+	it.user = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// User returns the current user.
+func (it *RoomUserIterator) User() User {
+	return it.user
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *RoomUserIterator) Err() error {
+	return it.err
+}
+
+func (it *RoomUserIterator) fetch() {
+	options := it.options
+	users, err := it.gitter.ListRoomUsersContext(it.ctx, it.roomID, &options)
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if len(users) == 0 {
+		it.done = true
+		return
+	}
+
+	it.options.Skip += len(users)
+	it.page = users
+}
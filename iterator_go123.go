@@ -0,0 +1,69 @@
+//go:build go1.23
+
+package gitter
+
+import (
+	"context"
+	"iter"
+)
+
+// All returns the remaining messages of the iterator as a sequence.
+// An error stopping the iteration is yielded as the last element.
+//
+// For example:
+//
+//	for message, err := range api.NewMessageIterator("roomID", nil).All() {
+//		if err != nil {
+//			// handle error
+//		}
+//		fmt.Println(message.Text)
+//	}
+func (it *MessageIterator) All() iter.Seq2[Message, error] {
+	return func(yield func(Message, error) bool) {
+		for it.Next() {
+			if !yield(it.Message(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(Message{}, err)
+		}
+	}
+}
+
+// AllMessages returns the messages of a room as a sequence.
+// Options are optional. You can pass nil or specific options.
+func (gitter *Gitter) AllMessages(roomID string, options *MessageIteratorOptions) iter.Seq2[Message, error] {
+	return gitter.AllMessagesContext(context.Background(), roomID, options)
+}
+
+// AllMessagesContext is like AllMessages but uses ctx for the underlying requests.
+func (gitter *Gitter) AllMessagesContext(ctx context.Context, roomID string, options *MessageIteratorOptions) iter.Seq2[Message, error] {
+	return gitter.NewMessageIteratorContext(ctx, roomID, options).All()
+}
+
+// All returns the remaining users of the iterator as a sequence.
+// An error stopping the iteration is yielded as the last element.
+func (it *RoomUserIterator) All() iter.Seq2[User, error] {
+	return func(yield func(User, error) bool) {
+		for it.Next() {
+			if !yield(it.User(), nil) {
+				return
+			}
+		}
+		if err := it.Err(); err != nil {
+			yield(User{}, err)
+		}
+	}
+}
+
+// AllRoomUsers returns the users of a room as a sequence.
+// Options are optional. You can pass nil or specific options.
+func (gitter *Gitter) AllRoomUsers(roomID string, options *RoomUsersOptions) iter.Seq2[User, error] {
+	return gitter.AllRoomUsersContext(context.Background(), roomID, options)
+}
+
+// AllRoomUsersContext is like AllRoomUsers but uses ctx for the underlying requests.
+func (gitter *Gitter) AllRoomUsersContext(ctx context.Context, roomID string, options *RoomUsersOptions) iter.Seq2[User, error] {
+	return gitter.NewRoomUserIteratorContext(ctx, roomID, options).All()
+}
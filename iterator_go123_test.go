@@ -0,0 +1,51 @@
+//go:build go1.23
+
+package gitter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllMessages(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5"}, false)
+
+	var ids []string
+	for message, err := range gitter.AllMessages("xyz", &MessageIteratorOptions{PageSize: 2}) {
+		if err != nil {
+			t.Fatalf("Expected %v, got %v", nil, err)
+		}
+		ids = append(ids, message.ID)
+		if len(ids) == 4 {
+			break
+		}
+	}
+
+	wanted := []string{"5", "4", "3", "2"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestAllRoomUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveRoomUsers([]string{"1", "2", "3"})
+
+	var ids []string
+	for user, err := range gitter.AllRoomUsers("xyz", &RoomUsersOptions{Limit: 2}) {
+		if err != nil {
+			t.Fatalf("Expected %v, got %v", nil, err)
+		}
+		ids = append(ids, user.ID)
+	}
+
+	wanted := []string{"1", "2", "3"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
@@ -0,0 +1,209 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// serveHistory fakes the chatMessages endpoint of room xyz for a history of
+// message IDs. If inclusive is set the beforeId/afterId message is returned
+// too, producing overlapping pages.
+func serveHistory(t *testing.T, ids []string, inclusive bool) {
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		index := func(id string) int {
+			for i, v := range ids {
+				if v == id {
+					return i
+				}
+			}
+			t.Errorf("Unknown message ID %v", id)
+			return 0
+		}
+
+		start, end := 0, len(ids)
+		if beforeID := query.Get("beforeId"); beforeID != "" {
+			end = index(beforeID)
+			if inclusive {
+				end++
+			}
+		}
+		if afterID := query.Get("afterId"); afterID != "" {
+			start = index(afterID) + 1
+			if inclusive {
+				start--
+			}
+			if end-start > limit {
+				end = start + limit
+			}
+		}
+		if end-start > limit {
+			start = end - limit
+		}
+
+		var messages []Message
+		for _, id := range ids[start:end] {
+			messages = append(messages, Message{ID: id})
+		}
+		if messages == nil {
+			messages = []Message{}
+		}
+		json.NewEncoder(w).Encode(messages)
+	})
+}
+
+func collectIDs(it *MessageIterator) []string {
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Message().ID)
+	}
+	return ids
+}
+
+func TestMessageIterator_backward(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5", "6", "7"}, false)
+
+	it := gitter.NewMessageIterator("xyz", &MessageIteratorOptions{PageSize: 3})
+	ids := collectIDs(it)
+	if it.Err() != nil {
+		t.Errorf("Expected %v, got %v", nil, it.Err())
+	}
+
+	wanted := []string{"7", "6", "5", "4", "3", "2", "1"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestMessageIterator_forward(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5", "6", "7"}, false)
+
+	it := gitter.NewMessageIterator("xyz", &MessageIteratorOptions{
+		Direction: Forward,
+		StartID:   "2",
+		PageSize:  3,
+	})
+	ids := collectIDs(it)
+	if it.Err() != nil {
+		t.Errorf("Expected %v, got %v", nil, it.Err())
+	}
+
+	wanted := []string{"3", "4", "5", "6", "7"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestMessageIterator_forwardFromOldest(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5", "6", "7"}, false)
+
+	it := gitter.NewMessageIterator("xyz", &MessageIteratorOptions{
+		Direction: Forward,
+		PageSize:  3,
+	})
+	ids := collectIDs(it)
+	if it.Err() != nil {
+		t.Errorf("Expected %v, got %v", nil, it.Err())
+	}
+
+	wanted := []string{"1", "2", "3", "4", "5", "6", "7"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestMessageIterator_forwardFromOldestOverlapping(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5", "6", "7"}, true)
+
+	it := gitter.NewMessageIterator("xyz", &MessageIteratorOptions{
+		Direction: Forward,
+		PageSize:  3,
+	})
+	ids := collectIDs(it)
+	if it.Err() != nil {
+		t.Errorf("Expected %v, got %v", nil, it.Err())
+	}
+
+	wanted := []string{"1", "2", "3", "4", "5", "6", "7"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestMessageIterator_deduplicate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5", "6", "7"}, true)
+
+	it := gitter.NewMessageIterator("xyz", &MessageIteratorOptions{PageSize: 3})
+	ids := collectIDs(it)
+	if it.Err() != nil {
+		t.Errorf("Expected %v, got %v", nil, it.Err())
+	}
+
+	wanted := []string{"7", "6", "5", "4", "3", "2", "1"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestMessageIterator_canceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveHistory(t, []string{"1", "2", "3", "4", "5", "6", "7"}, false)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := gitter.NewMessageIteratorContext(ctx, "xyz", &MessageIteratorOptions{PageSize: 3})
+	if !it.Next() {
+		t.Fatalf("Expected first message, got %v", it.Err())
+	}
+	cancel()
+
+	ids := collectIDs(it)
+	if len(ids) != 2 {
+		t.Errorf("Expected %v, got %v", 2, len(ids))
+	}
+
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, it.Err())
+	}
+}
+
+func TestMessageIterator_error(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	it := gitter.NewMessageIterator("xyz", nil)
+	if it.Next() {
+		t.Errorf("Expected %v, got %v", false, true)
+	}
+
+	if !errors.Is(it.Err(), ErrNotFound) {
+		t.Errorf("Expected %v, got %v", ErrNotFound, it.Err())
+	}
+}
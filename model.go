@@ -53,10 +53,103 @@ type Room struct {
 
 	RoomMember bool `json:"roomMember"`
 
+	// Who can see and join the room (PUBLIC, PRIVATE or INHERITED)
+	Security string `json:"security"`
+
+	// Indicates if the room is hidden from search engines
+	NoIndex bool `json:"noindex"`
+
+	// ID of the group (community) the room belongs to
+	GroupID string `json:"groupId"`
+
 	// Room version.
 	Version int `json:"v"`
 }
 
+// A Group in Gitter is a community that rooms are organized in.
+// It can be backed by a GitHub Organization or Repository.
+type Group struct {
+
+	// Group ID
+	ID string `json:"id"`
+
+	// Group name
+	Name string `json:"name"`
+
+	// Group URI on Gitter
+	URI string `json:"uri"`
+
+	// URI of the home room of the group
+	HomeURI string `json:"homeUri"`
+
+	// What the group is backed by
+	BackedBy GroupBackedBy `json:"backedBy"`
+
+	// Group avatar URL
+	AvatarURL string `json:"avatarUrl"`
+}
+
+// GroupBackedBy describes the GitHub entity backing a group
+type GroupBackedBy struct {
+
+	// Type of the backing entity
+	// - GH_ORG: A GitHub Organization.
+	// - GH_REPO: A GitHub Repository.
+	// - GH_USER: A GitHub User.
+	// Empty if the group is not backed by anything.
+	Type string `json:"type"`
+
+	// Path of the backing entity on GitHub
+	LinkPath string `json:"linkPath"`
+}
+
+// Org is a GitHub Organization the user belongs to
+type Org struct {
+
+	// GitHub Organization ID
+	ID int64 `json:"id"`
+
+	// Organization name
+	Name string `json:"name"`
+
+	// Organization avatar URL
+	AvatarURL string `json:"avatar_url"`
+
+	// Gitter room of the organization, nil if there is none
+	Room *Room `json:"room"`
+
+	// Indicates if the organization has a premium plan
+	Premium bool `json:"premium"`
+}
+
+// Repo is a GitHub Repository of the user
+type Repo struct {
+
+	// GitHub Repository ID
+	ID int64 `json:"id"`
+
+	// Full name of the repository, e.g. "owner/repo"
+	Name string `json:"name"`
+
+	// Repository description
+	Description string `json:"description"`
+
+	// Repository URI on Gitter
+	URI string `json:"uri"`
+
+	// Indicates if the repository is private
+	Private bool `json:"private"`
+
+	// Gitter room of the repository, nil if there is none
+	Room *Room `json:"room"`
+
+	// Indicates if the repository has a Gitter room
+	Exists bool `json:"exists"`
+
+	// Repository avatar URL
+	AvatarURL string `json:"avatar_url"`
+}
+
 type User struct {
 
 	// Gitter User ID
@@ -76,6 +169,46 @@ type User struct {
 
 	// User avatar URI (medium)
 	AvatarURLMedium string `json:"avatarUrlMedium"`
+
+	// Role of the user in the room, RoleAdmin for administrators.
+	// Only set when listing the users of a room.
+	Role string `json:"role"`
+
+	// Gravatar version, changes when the avatar changes
+	GravatarVersion string `json:"gv"`
+}
+
+// UserProfile holds the extended profile of a user
+type UserProfile struct {
+	User
+
+	// Company of the user
+	Company string `json:"company"`
+
+	// Location of the user
+	Location string `json:"location"`
+
+	// Website of the user
+	Website string `json:"website"`
+
+	// URL of the user's GitHub profile
+	Profile string `json:"profile"`
+
+	// GitHub statistics of the user
+	Github GithubProfile `json:"github"`
+}
+
+// GithubProfile holds the GitHub statistics of a user
+type GithubProfile struct {
+
+	// Number of followers on GitHub
+	Followers int `json:"followers"`
+
+	// Number of public repositories on GitHub
+	PublicRepos int `json:"public_repos"`
+
+	// Number of users followed on GitHub
+	Following int `json:"following"`
 }
 
 type Message struct {
@@ -113,10 +246,42 @@ type Message struct {
 	// List of #Issues referenced in the message
 	Issues []Issue `json:"issues"`
 
+	// Indicates if the message is a status (/me) message
+	Status bool `json:"status,omitempty"`
+
+	// ID of the parent message if the message is a thread reply
+	ParentID string `json:"parentId,omitempty"`
+
+	// Number of replies in the thread of the message
+	ThreadMessageCount int `json:"threadMessageCount,omitempty"`
+
 	// Version
 	Version int `json:"v"`
 }
 
+// Ban holds data about a user banned from a room
+type Ban struct {
+
+	// Banned user
+	User User `json:"user"`
+
+	// User that issued the ban
+	BannedBy User `json:"bannedBy"`
+
+	// ISO formatted date of the ban
+	DateBanned time.Time `json:"dateBanned"`
+}
+
+// UnreadItems holds the IDs of the unread messages of a user in a room
+type UnreadItems struct {
+
+	// IDs of unread messages
+	Chat []string `json:"chat"`
+
+	// IDs of unread messages mentioning the user
+	Mention []string `json:"mention"`
+}
+
 // Mention holds data about mentioned user in the message
 type Mention struct {
 
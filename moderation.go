@@ -0,0 +1,86 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+)
+
+// GetBans returns the users banned from a room
+func (gitter *Gitter) GetBans(roomID string) ([]Ban, error) {
+	return gitter.GetBansContext(context.Background(), roomID)
+}
+
+// GetBansContext is like GetBans but uses ctx for the underlying requests.
+func (gitter *Gitter) GetBansContext(ctx context.Context, roomID string) ([]Ban, error) {
+
+	var bans []Ban
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/bans")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &bans)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return bans, nil
+}
+
+// BanUser bans a user from a room and removes them from it
+func (gitter *Gitter) BanUser(roomID, username string) (*Ban, error) {
+	return gitter.BanUserContext(context.Background(), roomID, username)
+}
+
+// BanUserContext is like BanUser but uses ctx for the underlying requests.
+func (gitter *Gitter) BanUserContext(ctx context.Context, roomID, username string) (*Ban, error) {
+
+	payload := struct {
+		Username string `json:"username"`
+	}{username}
+	body, _ := json.Marshal(payload)
+	response, err := gitter.post(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/bans", body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var ban Ban
+	err = json.Unmarshal(response, &ban)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &ban, nil
+}
+
+// UnbanUser lifts the ban of a user from a room
+func (gitter *Gitter) UnbanUser(roomID, username string) error {
+	return gitter.UnbanUserContext(context.Background(), roomID, username)
+}
+
+// UnbanUserContext is like UnbanUser but uses ctx for the underlying requests.
+func (gitter *Gitter) UnbanUserContext(ctx context.Context, roomID, username string) error {
+
+	_, err := gitter.delete(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/bans/"+url.PathEscape(username))
+	if err != nil {
+		gitter.log(err)
+		return err
+	}
+
+	return nil
+}
+
+// KickUser removes a user from a room without banning them
+func (gitter *Gitter) KickUser(roomID, userID string) error {
+	return gitter.KickUserContext(context.Background(), roomID, userID)
+}
+
+// KickUserContext is like KickUser but uses ctx for the underlying requests.
+func (gitter *Gitter) KickUserContext(ctx context.Context, roomID, userID string) error {
+	return gitter.LeaveRoomContext(ctx, roomID, userID)
+}
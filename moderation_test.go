@@ -0,0 +1,114 @@
+package gitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetBans(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/bans", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            [
+                {
+                    "user": {"id": "123", "username": "spammer"},
+                    "bannedBy": {"id": "456", "username": "admin"},
+                    "dateBanned": "2017-08-28T10:00:00.000Z"
+                }
+            ]
+        `)
+	})
+
+	b, err := gitter.GetBans("xyz")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(b) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(b))
+	}
+
+	if b[0].User.Username != "spammer" {
+		t.Errorf("Expected %v, got %v", "spammer", b[0].User.Username)
+	}
+
+	if b[0].BannedBy.Username != "admin" {
+		t.Errorf("Expected %v, got %v", "admin", b[0].BannedBy.Username)
+	}
+
+	if b[0].DateBanned.Year() != 2017 {
+		t.Errorf("Expected %v, got %v", 2017, b[0].DateBanned.Year())
+	}
+}
+
+func TestBanUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/bans", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected %v, got %v", "POST", r.Method)
+		}
+
+		var payload struct {
+			Username string `json:"username"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Username != "spammer" {
+			t.Errorf("Expected %v, got %v", "spammer", payload.Username)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "user": {"id": "123", "username": "spammer"}
+            }
+        `)
+	})
+
+	b, err := gitter.BanUser("xyz", "spammer")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if b.User.ID != "123" {
+		t.Errorf("Expected %v, got %v", "123", b.User.ID)
+	}
+}
+
+func TestUnbanUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/bans/spammer", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected %v, got %v", "DELETE", r.Method)
+		}
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	err := gitter.UnbanUser("xyz", "spammer")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestKickUser(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/users/123", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected %v, got %v", "DELETE", r.Method)
+		}
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	err := gitter.KickUser("xyz", "123")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
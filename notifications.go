@@ -0,0 +1,83 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// NotificationMode controls which messages of a room notify the user
+type NotificationMode string
+
+// Notification modes
+const (
+	// Notify on every message
+	NotifyAll NotificationMode = "all"
+
+	// Notify on mentions and @/all announcements only
+	NotifyAnnouncement NotificationMode = "announcement"
+
+	// Notify on direct mentions only
+	NotifyMute NotificationMode = "mute"
+)
+
+// NotificationSettings holds the notification settings of a user in a room
+type NotificationSettings struct {
+
+	// Notification mode
+	Mode NotificationMode `json:"mode"`
+
+	// Indicates if the room is muted, see Room.Lurk
+	Lurk bool `json:"lurk"`
+}
+
+// GetNotificationSettings returns the notification settings of the user in a room
+func (gitter *Gitter) GetNotificationSettings(userID, roomID string) (*NotificationSettings, error) {
+	return gitter.GetNotificationSettingsContext(context.Background(), userID, roomID)
+}
+
+// GetNotificationSettingsContext is like GetNotificationSettings but uses ctx for the underlying requests.
+func (gitter *Gitter) GetNotificationSettingsContext(ctx context.Context, userID, roomID string) (*NotificationSettings, error) {
+
+	var settings NotificationSettings
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/rooms/"+roomID+"/settings/notifications")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &settings)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// SetNotificationSettings changes the notification mode of the user in a room
+func (gitter *Gitter) SetNotificationSettings(userID, roomID string, mode NotificationMode) (*NotificationSettings, error) {
+	return gitter.SetNotificationSettingsContext(context.Background(), userID, roomID, mode)
+}
+
+// SetNotificationSettingsContext is like SetNotificationSettings but uses ctx for the underlying requests.
+func (gitter *Gitter) SetNotificationSettingsContext(ctx context.Context, userID, roomID string, mode NotificationMode) (*NotificationSettings, error) {
+
+	payload := struct {
+		Mode NotificationMode `json:"mode"`
+	}{mode}
+	body, _ := json.Marshal(payload)
+	response, err := gitter.put(ctx, gitter.config.apiBaseURL+"user/"+userID+"/rooms/"+roomID+"/settings/notifications", body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var settings NotificationSettings
+	err = json.Unmarshal(response, &settings)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &settings, nil
+}
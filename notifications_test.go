@@ -0,0 +1,68 @@
+package gitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetNotificationSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/rooms/xyz/settings/notifications", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "mode": "announcement",
+                "lurk": false
+            }
+        `)
+	})
+
+	s, err := gitter.GetNotificationSettings("abc", "xyz")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if s.Mode != NotifyAnnouncement {
+		t.Errorf("Expected %v, got %v", NotifyAnnouncement, s.Mode)
+	}
+}
+
+func TestSetNotificationSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/rooms/xyz/settings/notifications", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("Expected %v, got %v", "PUT", r.Method)
+		}
+
+		var settings NotificationSettings
+		json.NewDecoder(r.Body).Decode(&settings)
+		if settings.Mode != NotifyMute {
+			t.Errorf("Expected %v, got %v", NotifyMute, settings.Mode)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "mode": "mute",
+                "lurk": true
+            }
+        `)
+	})
+
+	s, err := gitter.SetNotificationSettings("abc", "xyz", NotifyMute)
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if s.Mode != NotifyMute {
+		t.Errorf("Expected %v, got %v", NotifyMute, s.Mode)
+	}
+
+	if !s.Lurk {
+		t.Errorf("Expected %v, got %v", true, s.Lurk)
+	}
+}
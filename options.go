@@ -0,0 +1,82 @@
+package gitter
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures the client created by NewWithOptions.
+type Option func(*Gitter)
+
+// WithAPIBaseURL sets the base URL of the REST API, for example to target a
+// self-hosted server or a test double.
+func WithAPIBaseURL(baseURL string) Option {
+	return func(gitter *Gitter) {
+		gitter.config.apiBaseURL = withTrailingSlash(baseURL)
+	}
+}
+
+// WithStreamBaseURL sets the base URL of the streaming API.
+func WithStreamBaseURL(baseURL string) Option {
+	return func(gitter *Gitter) {
+		gitter.config.streamBaseURL = withTrailingSlash(baseURL)
+	}
+}
+
+// WithFayeBaseURL sets the URL of the Faye endpoint.
+func WithFayeBaseURL(baseURL string) Option {
+	return func(gitter *Gitter) {
+		gitter.config.fayeBaseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the http client used for all requests.
+// Timeouts set with WithTimeouts are ignored in that case.
+func WithHTTPClient(client *http.Client) Option {
+	return func(gitter *Gitter) {
+		gitter.config.client = client
+	}
+}
+
+// WithTimeouts sets the connect and read/write timeouts of the default http client.
+func WithTimeouts(connect, readWrite time.Duration) Option {
+	return func(gitter *Gitter) {
+		gitter.config.connectTimeout = connect
+		gitter.config.readWriteTimeout = readWrite
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(gitter *Gitter) {
+		gitter.config.userAgent = userAgent
+	}
+}
+
+// WithLogger enables debug output and writes it to logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(gitter *Gitter) {
+		gitter.debug = true
+		gitter.logger = logger
+	}
+}
+
+// WithRetryPolicy sets how failed requests are retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(gitter *Gitter) {
+		gitter.config.retryPolicy = policy
+	}
+}
+
+// WithRateLimitPolicy sets how the client reacts to exhausted rate limits.
+func WithRateLimitPolicy(policy RateLimitPolicy) Option {
+	return func(gitter *Gitter) {
+		gitter.config.rateLimitPolicy = policy
+	}
+}
+
+func withTrailingSlash(baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/"
+}
@@ -0,0 +1,103 @@
+package gitter
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWithOptions_defaults(t *testing.T) {
+	g := NewWithOptions("abc")
+
+	if g.config.apiBaseURL != apiBaseURL {
+		t.Errorf("Expected %v, got %v", apiBaseURL, g.config.apiBaseURL)
+	}
+
+	if g.config.fayeBaseURL != fayeBaseURL {
+		t.Errorf("Expected %v, got %v", fayeBaseURL, g.config.fayeBaseURL)
+	}
+
+	if g.config.client == nil {
+		t.Errorf("Expected http client, got %v", nil)
+	}
+
+	if g.config.retryPolicy.MaxAttempts != DefaultRetryPolicy().MaxAttempts {
+		t.Errorf("Expected %v, got %v", DefaultRetryPolicy().MaxAttempts, g.config.retryPolicy.MaxAttempts)
+	}
+}
+
+func TestNewWithOptions_baseURLs(t *testing.T) {
+	g := NewWithOptions("abc",
+		WithAPIBaseURL("http://example.com/api"),
+		WithStreamBaseURL("http://example.com/stream/"),
+		WithFayeBaseURL("http://example.com/faye"),
+	)
+
+	if g.config.apiBaseURL != "http://example.com/api/" {
+		t.Errorf("Expected %v, got %v", "http://example.com/api/", g.config.apiBaseURL)
+	}
+
+	if g.config.streamBaseURL != "http://example.com/stream/" {
+		t.Errorf("Expected %v, got %v", "http://example.com/stream/", g.config.streamBaseURL)
+	}
+
+	if g.config.fayeBaseURL != "http://example.com/faye" {
+		t.Errorf("Expected %v, got %v", "http://example.com/faye", g.config.fayeBaseURL)
+	}
+}
+
+func TestNewWithOptions_httpClient(t *testing.T) {
+	c := &http.Client{}
+	g := NewWithOptions("abc", WithHTTPClient(c), WithTimeouts(time.Second, time.Second))
+
+	if g.config.client != c {
+		t.Errorf("Expected %v, got %v", c, g.config.client)
+	}
+}
+
+func TestNewWithOptions_policies(t *testing.T) {
+	g := NewWithOptions("abc",
+		WithRetryPolicy(RetryPolicy{}),
+		WithRateLimitPolicy(RateLimitPolicy{Wait: true}),
+	)
+
+	if g.config.retryPolicy.MaxAttempts != 0 {
+		t.Errorf("Expected %v, got %v", 0, g.config.retryPolicy.MaxAttempts)
+	}
+
+	if !g.config.rateLimitPolicy.Wait {
+		t.Errorf("Expected %v, got %v", true, g.config.rateLimitPolicy.Wait)
+	}
+}
+
+func TestNewWithOptions_userAgentAndLogger(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") != "bot/1.0" {
+			t.Errorf("Expected %v, got %v", "bot/1.0", r.Header.Get("User-Agent"))
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	var buf bytes.Buffer
+	g := NewWithOptions("abc",
+		WithAPIBaseURL(server.URL),
+		WithUserAgent("bot/1.0"),
+		WithLogger(log.New(&buf, "", 0)),
+	)
+
+	_, err := g.get(context.Background(), g.config.apiBaseURL)
+	if err == nil {
+		t.Errorf("Expected error, got %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Status code: 404") {
+		t.Errorf("Expected log to contain %v, got %v", "Status code: 404", buf.String())
+	}
+}
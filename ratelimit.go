@@ -0,0 +1,171 @@
+package gitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimit holds the request budget reported by Gitter in the
+// X-RateLimit-* headers of the last response.
+type RateLimit struct {
+
+	// Maximum number of requests allowed in the current window
+	Limit int
+
+	// Number of requests left in the current window
+	Remaining int
+
+	// Time at which the current window resets
+	Reset time.Time
+}
+
+// RateLimitPolicy controls how requests behave once the rate limit is exhausted.
+type RateLimitPolicy struct {
+
+	// Wait blocks requests until the rate limit window resets instead of
+	// failing with ErrRateLimited.
+	Wait bool
+
+	// MaxWait is the longest a single wait may take. A request that would need
+	// to wait longer fails immediately with ErrRateLimited. Zero means no limit.
+	MaxWait time.Duration
+
+	// MaxRetries is how many times a request rejected with 429 is sent again
+	// before it fails with ErrRateLimited. Zero means defaultRateLimitRetries,
+	// a negative value disables these retries.
+	MaxRetries int
+}
+
+// defaultRateLimitRetries is used when RateLimitPolicy.MaxRetries is zero.
+const defaultRateLimitRetries = 5
+
+// defaultRateLimitDelay is used when a 429 response carries no hint of when to retry.
+var defaultRateLimitDelay = time.Second
+
+// minRateLimitDelay keeps a 429 response with a zero or past reset from
+// being retried in a tight loop.
+var minRateLimitDelay = 100 * time.Millisecond
+
+// SetRateLimitPolicy sets how the client reacts to exhausted rate limits.
+// It is safe to call while requests are in flight.
+func (gitter *Gitter) SetRateLimitPolicy(policy RateLimitPolicy) {
+	gitter.mu.Lock()
+	defer gitter.mu.Unlock()
+	gitter.config.rateLimitPolicy = policy
+}
+
+// RateLimit returns the rate limit reported by the last API response.
+func (gitter *Gitter) RateLimit() RateLimit {
+	gitter.mu.Lock()
+	defer gitter.mu.Unlock()
+	return gitter.rateLimit
+}
+
+func (gitter *Gitter) updateRateLimit(header http.Header) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	reset := parseRateLimitReset(header.Get("X-RateLimit-Reset"))
+
+	gitter.mu.Lock()
+	gitter.rateLimit = RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}
+	gitter.mu.Unlock()
+}
+
+// parseRateLimitReset accepts the reset time as a unix timestamp in either
+// seconds or milliseconds.
+func parseRateLimitReset(value string) time.Time {
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || reset <= 0 {
+		return time.Time{}
+	}
+	if reset > 1e12 {
+		return time.Unix(0, reset*int64(time.Millisecond))
+	}
+	return time.Unix(reset, 0)
+}
+
+// waitRateLimit blocks until the rate limit window resets if the budget is
+// exhausted and the policy asks to wait. It fails with an APIError matching
+// ErrRateLimited if the wait would exceed MaxWait.
+func (gitter *Gitter) waitRateLimit(ctx context.Context, policy RateLimitPolicy, method, url string) error {
+	if !policy.Wait {
+		return nil
+	}
+
+	rateLimit := gitter.RateLimit()
+	if rateLimit.Limit == 0 || rateLimit.Remaining > 0 {
+		return nil
+	}
+
+	delay := time.Until(rateLimit.Reset)
+	if delay <= 0 {
+		return nil
+	}
+	if policy.MaxWait > 0 && delay > policy.MaxWait {
+		return APIError{
+			What:       fmt.Sprintf("Rate limit exhausted, resets in %v", delay),
+			StatusCode: http.StatusTooManyRequests,
+			Method:     method,
+			URL:        url,
+		}
+	}
+
+	gitter.log("Rate limit exhausted, waiting " + delay.String())
+	return sleep(ctx, delay)
+}
+
+// rateLimitDelay returns how long to wait before retrying a request that
+// failed with 429 after the given number of retries, or false if the request
+// should not be retried.
+func (gitter *Gitter) rateLimitDelay(policy RateLimitPolicy, err error, retries int) (time.Duration, bool) {
+	var apiErr APIError
+	if !policy.Wait || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	maxRetries := policy.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = defaultRateLimitRetries
+	}
+	if retries >= maxRetries {
+		return 0, false
+	}
+
+	delay := defaultRateLimitDelay
+	if seconds, err := strconv.Atoi(apiErr.Header.Get("Retry-After")); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if reset := parseRateLimitReset(apiErr.Header.Get("X-RateLimit-Reset")); !reset.IsZero() {
+		delay = time.Until(reset)
+	}
+	if delay < minRateLimitDelay {
+		delay = minRateLimitDelay
+	}
+
+	if policy.MaxWait > 0 && delay > policy.MaxWait {
+		return 0, false
+	}
+	return delay, true
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
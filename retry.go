@@ -0,0 +1,131 @@
+package gitter
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy controls how failed REST requests are retried.
+// The zero value disables retries.
+type RetryPolicy struct {
+
+	// Total number of attempts, including the first one. Values below 2
+	// disable retries.
+	MaxAttempts int
+
+	// Delay before the first retry. It doubles on every following attempt.
+	BaseDelay time.Duration
+
+	// Upper bound of the delay between attempts. Zero means no bound.
+	MaxDelay time.Duration
+
+	// Fraction (0 to 1) by which every delay is randomly shortened, to keep
+	// many clients from retrying in lockstep.
+	Jitter float64
+
+	// Backoff, if set, replaces the exponential curve. It receives the number
+	// of the retry, starting at 1, and returns the delay before it.
+	Backoff func(retry int) time.Duration
+
+	// Response status codes that are retried. Connection errors are always
+	// retried, errors reading a successful response never are.
+	StatusCodes []int
+
+	// HTTP methods that are safe to retry. POST is not idempotent and should
+	// only be added if sending the same request twice is acceptable.
+	Methods []string
+}
+
+// DefaultRetryPolicy returns the policy used by New: up to three attempts of
+// idempotent requests failing with a connection error or a 5xx gateway error.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		StatusCodes: []int{
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		Methods: []string{"GET", "HEAD", "OPTIONS", "PUT", "DELETE"},
+	}
+}
+
+// SetRetryPolicy sets how failed requests are retried.
+// Pass RetryPolicy{} to disable retries. It is safe to call while requests
+// are in flight.
+func (gitter *Gitter) SetRetryPolicy(policy RetryPolicy) {
+	gitter.mu.Lock()
+	defer gitter.mu.Unlock()
+	gitter.config.retryPolicy = policy
+}
+
+// shouldRetry reports whether a request that failed with err on the given
+// attempt should be sent again.
+func (policy RetryPolicy) shouldRetry(ctx context.Context, method string, attempt int, err error) bool {
+	if err == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+		return false
+	}
+	if !containsString(policy.Methods, method) {
+		return false
+	}
+
+	var apiErr APIError
+	if !errors.As(err, &apiErr) {
+		// only connection errors, as returned by the http client, are
+		// worth another attempt
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+	for _, code := range policy.StatusCodes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the time to wait before the given retry, starting at 1.
+func (policy RetryPolicy) delay(retry int) time.Duration {
+	if policy.Backoff != nil {
+		return policy.Backoff(retry)
+	}
+	return backoff(policy.BaseDelay, policy.MaxDelay, policy.Jitter, retry)
+}
+
+// backoff computes an exponentially growing delay, capped at max and
+// randomly shortened by up to jitter.
+func backoff(base, max time.Duration, jitter float64, retry int) time.Duration {
+	if base <= 0 || retry < 1 {
+		return 0
+	}
+
+	d := float64(base) * math.Pow(2, float64(retry-1))
+	if max > 0 && d > float64(max) {
+		d = float64(max)
+	}
+	if jitter > 0 {
+		if jitter > 1 {
+			jitter = 1
+		}
+		d -= d * jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,282 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+)
+
+// Room security types
+const (
+	SecurityPublic    = "PUBLIC"
+	SecurityPrivate   = "PRIVATE"
+	SecurityInherited = "INHERITED"
+)
+
+// RoomCreate holds the properties of a new room
+type RoomCreate struct {
+
+	// Room name
+	Name string `json:"name"`
+
+	// Room topic
+	Topic string `json:"topic,omitempty"`
+
+	// Who can see and join the room: SecurityPublic, SecurityPrivate or SecurityInherited
+	Security string `json:"security,omitempty"`
+}
+
+// RoomUpdate holds the room properties to change. Nil fields are left unchanged.
+type RoomUpdate struct {
+
+	// Room topic. Point to an empty string to clear it.
+	Topic *string
+
+	// Tags that define the room. A non-nil empty slice removes all tags.
+	Tags []string
+
+	// Hide the room from search engines
+	NoIndex *bool
+}
+
+// WelcomeMessage is shown to users joining a room
+type WelcomeMessage struct {
+
+	// Original message in plain-text/markdown
+	Text string `json:"text"`
+
+	// HTML formatted message
+	HTML string `json:"html"`
+}
+
+// CreateRoom creates a new room owned by the user
+func (gitter *Gitter) CreateRoom(userID string, room RoomCreate) (*Room, error) {
+	return gitter.CreateRoomContext(context.Background(), userID, room)
+}
+
+// CreateRoomContext is like CreateRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) CreateRoomContext(ctx context.Context, userID string, room RoomCreate) (*Room, error) {
+
+	body, _ := json.Marshal(room)
+	response, err := gitter.post(ctx, gitter.config.apiBaseURL+"user/"+userID+"/channels", body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var created Room
+	err = json.Unmarshal(response, &created)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+// UpdateRoom updates the topic, tags or indexing of a room
+func (gitter *Gitter) UpdateRoom(roomID string, update RoomUpdate) (*Room, error) {
+	return gitter.UpdateRoomContext(context.Background(), roomID, update)
+}
+
+// UpdateRoomContext is like UpdateRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) UpdateRoomContext(ctx context.Context, roomID string, update RoomUpdate) (*Room, error) {
+
+	payload := struct {
+		Topic   *string `json:"topic,omitempty"`
+		Tags    *string `json:"tags,omitempty"`
+		NoIndex *bool   `json:"noindex,omitempty"`
+	}{
+		Topic:   update.Topic,
+		NoIndex: update.NoIndex,
+	}
+	if update.Tags != nil {
+		// Gitter expects the tags as a comma separated list
+		tags := strings.Join(update.Tags, ",")
+		payload.Tags = &tags
+	}
+	body, _ := json.Marshal(payload)
+	response, err := gitter.put(ctx, gitter.config.apiBaseURL+"rooms/"+roomID, body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var room Room
+	err = json.Unmarshal(response, &room)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &room, nil
+}
+
+// DeleteRoom deletes a room
+func (gitter *Gitter) DeleteRoom(roomID string) error {
+	return gitter.DeleteRoomContext(context.Background(), roomID)
+}
+
+// DeleteRoomContext is like DeleteRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) DeleteRoomContext(ctx context.Context, roomID string) error {
+
+	_, err := gitter.delete(ctx, gitter.config.apiBaseURL+"rooms/"+roomID)
+	if err != nil {
+		gitter.log(err)
+		return err
+	}
+
+	gitter.forgetRoom(roomID)
+	return nil
+}
+
+// JoinRoomByURI joins the room with the passed URI, e.g. "gitterhq/sandbox", and returns it
+func (gitter *Gitter) JoinRoomByURI(uri string) (*Room, error) {
+	return gitter.JoinRoomByURIContext(context.Background(), uri)
+}
+
+// JoinRoomByURIContext is like JoinRoomByURI but uses ctx for the underlying requests.
+func (gitter *Gitter) JoinRoomByURIContext(ctx context.Context, uri string) (*Room, error) {
+
+	room, err := gitter.joinRoomByURI(ctx, uri)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	gitter.cacheRoomID(uri, *room)
+	return room, nil
+}
+
+// joinRoomByURI joins the room without caching its ID.
+func (gitter *Gitter) joinRoomByURI(ctx context.Context, uri string) (*Room, error) {
+
+	payload := struct {
+		URI string `json:"uri"`
+	}{uri}
+	body, _ := json.Marshal(payload)
+	response, err := gitter.post(ctx, gitter.config.apiBaseURL+"rooms", body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var room Room
+	err = json.Unmarshal(response, &room)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &room, nil
+}
+
+// ResolveRoom returns the current state of the room with the passed URI.
+// The first lookup of a URI joins the room, like JoinRoomByURI, and caches
+// its ID. Later lookups fetch the room by ID and don't join it again, e.g.
+// after leaving it.
+func (gitter *Gitter) ResolveRoom(uri string) (*Room, error) {
+	return gitter.ResolveRoomContext(context.Background(), uri)
+}
+
+// ResolveRoomContext is like ResolveRoom but uses ctx for the underlying requests.
+func (gitter *Gitter) ResolveRoomContext(ctx context.Context, uri string) (*Room, error) {
+
+	if roomID, ok := gitter.cachedRoomID(uri); ok {
+		return gitter.GetRoomContext(ctx, roomID)
+	}
+
+	return gitter.JoinRoomByURIContext(ctx, uri)
+}
+
+// roomCacheKey normalizes a room URI, which Gitter treats case-insensitively.
+func roomCacheKey(uri string) string {
+	return strings.ToLower(strings.Trim(uri, "/"))
+}
+
+func (gitter *Gitter) cachedRoomID(uri string) (string, bool) {
+	gitter.mu.Lock()
+	defer gitter.mu.Unlock()
+	roomID, ok := gitter.roomIDs[roomCacheKey(uri)]
+	return roomID, ok
+}
+
+// cacheRoomID stores the room ID under the URI it was requested with and
+// under the room's own URI.
+func (gitter *Gitter) cacheRoomID(uri string, room Room) {
+	gitter.mu.Lock()
+	defer gitter.mu.Unlock()
+	if gitter.roomIDs == nil {
+		gitter.roomIDs = make(map[string]string)
+	}
+	gitter.roomIDs[roomCacheKey(uri)] = room.ID
+	if room.URI != "" {
+		gitter.roomIDs[roomCacheKey(room.URI)] = room.ID
+	}
+}
+
+func (gitter *Gitter) forgetRoom(roomID string) {
+	gitter.mu.Lock()
+	defer gitter.mu.Unlock()
+	for uri, id := range gitter.roomIDs {
+		if id == roomID {
+			delete(gitter.roomIDs, uri)
+		}
+	}
+}
+
+// GetWelcomeMessage returns the welcome message of a room
+func (gitter *Gitter) GetWelcomeMessage(roomID string) (*WelcomeMessage, error) {
+	return gitter.GetWelcomeMessageContext(context.Background(), roomID)
+}
+
+// GetWelcomeMessageContext is like GetWelcomeMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) GetWelcomeMessageContext(ctx context.Context, roomID string) (*WelcomeMessage, error) {
+
+	var meta struct {
+		WelcomeMessage WelcomeMessage `json:"welcomeMessage"`
+	}
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/meta/welcome-message")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &meta)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &meta.WelcomeMessage, nil
+}
+
+// SetWelcomeMessage sets the welcome message of a room
+func (gitter *Gitter) SetWelcomeMessage(roomID, text string) (*WelcomeMessage, error) {
+	return gitter.SetWelcomeMessageContext(context.Background(), roomID, text)
+}
+
+// SetWelcomeMessageContext is like SetWelcomeMessage but uses ctx for the underlying requests.
+func (gitter *Gitter) SetWelcomeMessageContext(ctx context.Context, roomID, text string) (*WelcomeMessage, error) {
+
+	payload := struct {
+		WelcomeMessage string `json:"welcomeMessage"`
+	}{text}
+	body, _ := json.Marshal(payload)
+	response, err := gitter.put(ctx, gitter.config.apiBaseURL+"rooms/"+roomID+"/meta/welcome-message", body)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	var meta struct {
+		WelcomeMessage WelcomeMessage `json:"welcomeMessage"`
+	}
+	err = json.Unmarshal(response, &meta)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &meta.WelcomeMessage, nil
+}
@@ -0,0 +1,405 @@
+package gitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCreateRoom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/channels", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected %v, got %v", "POST", r.Method)
+		}
+
+		var room RoomCreate
+		json.NewDecoder(r.Body).Decode(&room)
+		if room.Name != "general" {
+			t.Errorf("Expected %v, got %v", "general", room.Name)
+		}
+
+		if room.Security != SecurityPrivate {
+			t.Errorf("Expected %v, got %v", SecurityPrivate, room.Security)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "name": "general",
+                "security": "PRIVATE"
+            }
+        `)
+	})
+
+	r, err := gitter.CreateRoom("abc", RoomCreate{Name: "general", Security: SecurityPrivate})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if r.ID != "xyz" {
+		t.Errorf("Expected %v, got %v", "xyz", r.ID)
+	}
+
+	if r.Security != SecurityPrivate {
+		t.Errorf("Expected %v, got %v", SecurityPrivate, r.Security)
+	}
+}
+
+func TestUpdateRoom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("Expected %v, got %v", "PUT", r.Method)
+		}
+
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["topic"] != "new topic" {
+			t.Errorf("Expected %v, got %v", "new topic", payload["topic"])
+		}
+
+		if payload["tags"] != "go,api" {
+			t.Errorf("Expected %v, got %v", "go,api", payload["tags"])
+		}
+
+		if payload["noindex"] != true {
+			t.Errorf("Expected %v, got %v", true, payload["noindex"])
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "topic": "new topic",
+                "tags": ["go", "api"],
+                "noindex": true
+            }
+        `)
+	})
+
+	topic := "new topic"
+	noIndex := true
+	r, err := gitter.UpdateRoom("xyz", RoomUpdate{
+		Topic:   &topic,
+		Tags:    []string{"go", "api"},
+		NoIndex: &noIndex,
+	})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if r.Topic != "new topic" {
+		t.Errorf("Expected %v, got %v", "new topic", r.Topic)
+	}
+
+	if len(r.Tags) != 2 {
+		t.Errorf("Expected %v, got %v", 2, len(r.Tags))
+	}
+
+	if !r.NoIndex {
+		t.Errorf("Expected %v, got %v", true, r.NoIndex)
+	}
+}
+
+func TestUpdateRoom_clear(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		wanted := map[string]interface{}{"topic": "", "tags": ""}
+		if !reflect.DeepEqual(payload, wanted) {
+			t.Errorf("Expected %v, got %v", wanted, payload)
+		}
+
+		fmt.Fprint(w, `{"id": "xyz"}`)
+	})
+
+	topic := ""
+	_, err := gitter.UpdateRoom("xyz", RoomUpdate{
+		Topic: &topic,
+		Tags:  []string{},
+	})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestUpdateRoom_unchanged(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if len(payload) != 0 {
+			t.Errorf("Expected %v, got %v", 0, len(payload))
+		}
+
+		fmt.Fprint(w, `{"id": "xyz"}`)
+	})
+
+	_, err := gitter.UpdateRoom("xyz", RoomUpdate{})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestDeleteRoom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("Expected %v, got %v", "DELETE", r.Method)
+		}
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	err := gitter.DeleteRoom("xyz")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestGetWelcomeMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/meta/welcome-message", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "welcomeMessage": {
+                    "text": "Welcome!",
+                    "html": "<p>Welcome!</p>"
+                }
+            }
+        `)
+	})
+
+	m, err := gitter.GetWelcomeMessage("xyz")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if m.Text != "Welcome!" {
+		t.Errorf("Expected %v, got %v", "Welcome!", m.Text)
+	}
+
+	if m.HTML != "<p>Welcome!</p>" {
+		t.Errorf("Expected %v, got %v", "<p>Welcome!</p>", m.HTML)
+	}
+}
+
+func TestSetWelcomeMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/meta/welcome-message", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" {
+			t.Errorf("Expected %v, got %v", "PUT", r.Method)
+		}
+
+		var payload struct {
+			WelcomeMessage string `json:"welcomeMessage"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.WelcomeMessage != "Hi" {
+			t.Errorf("Expected %v, got %v", "Hi", payload.WelcomeMessage)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "welcomeMessage": {
+                    "text": "Hi",
+                    "html": "Hi"
+                }
+            }
+        `)
+	})
+
+	m, err := gitter.SetWelcomeMessage("xyz", "Hi")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if m.Text != "Hi" {
+		t.Errorf("Expected %v, got %v", "Hi", m.Text)
+	}
+}
+
+func TestJoinRoomByURI(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected %v, got %v", "POST", r.Method)
+		}
+
+		var payload struct {
+			URI string `json:"uri"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.URI != "gitterhq/sandbox" {
+			t.Errorf("Expected %v, got %v", "gitterhq/sandbox", payload.URI)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "uri": "gitterHQ/sandbox"
+            }
+        `)
+	})
+
+	r, err := gitter.JoinRoomByURI("gitterhq/sandbox")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if r.ID != "xyz" {
+		t.Errorf("Expected %v, got %v", "xyz", r.ID)
+	}
+}
+
+func TestResolveRoom_cache(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var joins, gets int32
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&joins, 1)
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "uri": "gitterHQ/sandbox",
+                "userCount": 1
+            }
+        `)
+	})
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&gets, 1)
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "uri": "gitterHQ/sandbox",
+                "userCount": 2
+            }
+        `)
+	})
+
+	for i := 1; i <= 2; i++ {
+		r, err := gitter.ResolveRoom("gitterHQ/sandbox")
+		if err != nil {
+			t.Errorf("Expected %v, got %v", nil, err)
+		}
+
+		// joined first, then fetched by the cached ID
+		if r.UserCount != i {
+			t.Errorf("Expected %v, got %v", i, r.UserCount)
+		}
+	}
+
+	id, err := gitter.GetRoomId("gitterhq/sandbox")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if id != "xyz" {
+		t.Errorf("Expected %v, got %v", "xyz", id)
+	}
+
+	if n := atomic.LoadInt32(&joins); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+
+	if n := atomic.LoadInt32(&gets); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+}
+
+func TestGetRoomId_join(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var calls int32
+	mux.HandleFunc("/rooms", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Method != "POST" {
+			t.Errorf("Expected %v, got %v", "POST", r.Method)
+		}
+
+		fmt.Fprint(w, `
+            {
+                "id": "xyz",
+                "uri": "gitterhq/private"
+            }
+        `)
+	})
+
+	for i := 0; i < 3; i++ {
+		id, err := gitter.GetRoomId("gitterhq/private")
+		if err != nil {
+			t.Errorf("Expected %v, got %v", nil, err)
+		}
+
+		if id != "xyz" {
+			t.Errorf("Expected %v, got %v", "xyz", id)
+		}
+	}
+
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("Expected %v, got %v", 1, n)
+	}
+}
+
+func TestDeleteRoom_forgetsCachedRoom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	gitter.cacheRoomID("gitterhq/sandbox", Room{ID: "xyz", URI: "gitterhq/sandbox"})
+
+	err := gitter.DeleteRoom("xyz")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if _, ok := gitter.cachedRoomID("gitterhq/sandbox"); ok {
+		t.Errorf("Expected %v, got %v", false, ok)
+	}
+}
+
+func TestKickUser_keepsCachedRoom(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/users/abc", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	gitter.cacheRoomID("gitterhq/sandbox", Room{ID: "xyz", URI: "gitterhq/sandbox"})
+
+	err := gitter.KickUser("xyz", "abc")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if _, ok := gitter.cachedRoomID("gitterhq/sandbox"); !ok {
+		t.Errorf("Expected %v, got %v", true, ok)
+	}
+}
@@ -2,76 +2,221 @@ package gitter
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
-
-	"github.com/mreiferson/go-httpclient"
 )
 
-var defaultConnectionWaitTime time.Duration = 3000 // millis
-var defaultConnectionMaxRetries = 5
+// ErrStreamStarted is returned when running a stream that already ran.
+var ErrStreamStarted = errors.New("stream already started")
+
+// UnlimitedRetries makes a stream reconnect until it is closed.
+const UnlimitedRetries = -1
+
+// closedEventTimeout is how long a stream that was canceled waits for someone
+// to receive its GitterConnectionClosed event.
+var closedEventTimeout = 100 * time.Millisecond
+
+// StreamOptions controls how a stream reconnects after the connection drops
+// or fails. A zero Backoff or MaxRetries is replaced by the value of
+// DefaultStreamOptions, so StreamOptions{} behaves like Stream.
+type StreamOptions struct {
+
+	// Delay before the first reconnection attempt. It doubles on every
+	// following attempt.
+	Backoff time.Duration
+
+	// Number of consecutive failed reconnection attempts before the stream
+	// gives up, or UnlimitedRetries.
+	MaxRetries int
+
+	// Upper bound of the delay between attempts. Zero means no bound.
+	MaxDelay time.Duration
+
+	// Fraction (0 to 1) by which every delay is randomly shortened.
+	Jitter float64
+
+	// Don't fetch the messages missed while reconnecting. By default they
+	// are fetched with GetMessages and delivered before the live ones.
+	DisableResume bool
+}
+
+// DefaultStreamOptions returns the options used by Stream.
+func DefaultStreamOptions() StreamOptions {
+	return StreamOptions{
+		Backoff:    3 * time.Second,
+		MaxRetries: 5,
+		MaxDelay:   time.Minute,
+		Jitter:     0.2,
+	}
+}
 
 // Stream initialize stream
 func (gitter *Gitter) Stream(roomID string) *Stream {
+	return gitter.StreamWithOptions(roomID, DefaultStreamOptions())
+}
+
+// StreamWithOptions initialize stream with custom reconnection options
+//
+// For example:
+//
+//	stream := api.StreamWithOptions("roomID", gitter.StreamOptions{
+//		Backoff:    time.Second,
+//		MaxRetries: gitter.UnlimitedRetries,
+//		MaxDelay:   time.Minute,
+//		Jitter:     0.2,
+//	})
+func (gitter *Gitter) StreamWithOptions(roomID string, options StreamOptions) *Stream {
+	defaults := DefaultStreamOptions()
+	if options.Backoff <= 0 {
+		options.Backoff = defaults.Backoff
+	}
+	if options.MaxRetries == 0 {
+		options.MaxRetries = defaults.MaxRetries
+	}
+
 	return &Stream{
-		url:    streamBaseURL + "rooms/" + roomID + "/chatMessages",
-		Event:  make(chan Event),
-		gitter: gitter,
-		streamConnection: gitter.newStreamConnection(
-			defaultConnectionWaitTime,
-			defaultConnectionMaxRetries),
+		url:     gitter.config.streamBaseURL + "rooms/" + roomID + "/chatMessages",
+		roomID:  roomID,
+		Event:   make(chan Event),
+		gitter:  gitter,
+		options: options,
+		seen:    newRecentIDs(resumeHistorySize),
 	}
 }
 
-// Implemented to conform with https://developer.gitter.im/docs/streaming-api
+// Listen runs the stream until it is closed with Close.
+// It is a shorthand for stream.Run(context.Background()).
 func (gitter *Gitter) Listen(stream *Stream) {
+	stream.Run(context.Background())
+}
 
-	defer stream.destroy()
+// Run connects to the stream and pipes the received data to the Event
+// channel until ctx is done, Close is called or reconnecting fails more
+// than StreamOptions.MaxRetries times in a row. Every reconnection attempt
+// is announced with a Reconnecting event and a restored connection with a
+// Reconnected event. A GitterConnectionClosed event is sent last, after
+// which the Event channel is closed. Once ctx is done or Close is called,
+// the closing event is only delivered if the Event channel is still being
+// read, so callers may stop reading when they cancel.
+//
+// Unless StreamOptions.DisableResume is set, messages posted while
+// reconnecting are fetched and delivered once the connection is restored.
+// Messages delivered twice, by the backfill and the live connection, are
+// dropped.
+//
+// Run returns ctx.Err() if ctx is done, nil if the stream was closed with
+// Close and the last connection error otherwise. A stream can only run once.
+//
+// Implemented to conform with https://developer.gitter.im/docs/streaming-api
+func (stream *Stream) Run(ctx context.Context) error {
 
-	var reader *bufio.Reader
-	var gitterMessage Message
-	lastKeepalive := time.Now().Unix()
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream.mu.Lock()
+	if stream.started {
+		stream.mu.Unlock()
+		return ErrStreamStarted
+	}
+	stream.started = true
+	stream.cancel = cancel
+	if stream.closed {
+		cancel()
+	}
+	stream.mu.Unlock()
 
-	// connect
-	stream.connect()
+	defer stream.destroy()
 
-Loop:
+	var err error
+	attempt := 0
 	for {
 
-		// if closed then stop trying
-		if stream.isClosed() {
-			stream.Event <- Event{
-				Data: &GitterConnectionClosed{},
+		var resp *http.Response
+		resp, err = stream.connect(ctx)
+		if err == nil {
+			if attempt > 0 {
+				stream.gitter.log("Reconnected")
+				stream.send(ctx, Event{
+					Data: &Reconnected{Attempts: attempt},
+				})
+				if !stream.options.DisableResume {
+					stream.backfill(ctx)
+				}
 			}
-			break Loop
+			attempt = 0
+
+			// read until the connection drops, then reconnect
+			err = stream.read(ctx, resp)
 		}
-		
-		resp := stream.getResponse()
-		if resp.StatusCode != 200 {
-			gitter.log(fmt.Sprintf("Unexpected response code %v", resp.StatusCode))
-			continue
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		attempt++
+		if stream.options.MaxRetries != UnlimitedRetries && attempt > stream.options.MaxRetries {
+			stream.gitter.log("Number of retries exceeded the max retries number, we are done here")
+			break
+		}
+
+		delay := backoff(stream.options.Backoff, stream.options.MaxDelay, stream.options.Jitter, attempt)
+		stream.gitter.log(fmt.Sprintf("Reconnecting in %v, attempt %v: %v", delay, attempt, err))
+		stream.send(ctx, Event{
+			Data: &Reconnecting{
+				Attempt: attempt,
+				Delay:   delay,
+				Err:     err,
+			},
+		})
+		if sleep(ctx, delay) != nil {
+			break
 		}
-		
+	}
+
+	stream.sendClosed(ctx)
+	stream.gitter.log("Listening was completed")
+
+	if parent.Err() != nil {
+		return parent.Err()
+	}
+	if ctx.Err() != nil {
+		// closed with Close
+		return nil
+	}
+	return err
+}
+
+// read pipes the messages of one streamed response forward until the
+// connection drops, and returns the reason. A single reader is kept for the
+// whole connection, as one chunk of the body can hold several messages.
+func (stream *Stream) read(ctx context.Context, resp *http.Response) error {
+
+	defer resp.Body.Close()
+
+	gitter := stream.gitter
+	reader := bufio.NewReader(resp.Body)
+	lastKeepalive := time.Now().Unix()
+
+	for {
 		//"The JSON stream returns messages as JSON objects that are delimited by carriage return (\r)" <- Not true crap it's (\n) only
-		reader = bufio.NewReader(resp.Body)
 		line, err := reader.ReadBytes('\n')
 		if err != nil {
-			gitter.log("ReadBytes error: " + err.Error())
-			stream.connect()
-			continue
-		}
-
-		//Check if the line only consists of whitespace
-		onlyWhitespace := true
-		for _, b := range line {
-			if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
-				onlyWhitespace = false
+			if ctx.Err() != nil {
+				gitter.log("Stream closed")
+			} else {
+				gitter.log("ReadBytes error: " + err.Error())
 			}
+			return err
 		}
 
-		if onlyWhitespace {
+		if len(bytes.TrimSpace(line)) == 0 {
 			//"Parsers must be tolerant of occasional extra newline characters placed between messages."
 			currentKeepalive := time.Now().Unix() //interesting behavior of 100+ keepalives per seconds was observed
 			if currentKeepalive-lastKeepalive > 10 {
@@ -79,42 +224,126 @@ Loop:
 				gitter.log("Keepalive was received")
 			}
 			continue
-		} else if stream.isClosed() {
-			gitter.log("Stream closed")
-			continue
 		}
 
 		// unmarshal the streamed data
-		err = json.Unmarshal(line, &gitterMessage)
+		data, err := decodeEvent(line)
 		if err != nil {
 			gitter.log("JSON Unmarshal error: " + err.Error())
+			if !stream.send(ctx, Event{Data: &StreamError{Err: err}}) {
+				return ctx.Err()
+			}
 			continue
 		}
 
-		// we are here, then we got the good message. pipe it forward.
-		stream.Event <- Event{
-			Data: &MessageReceived{
-				Message: gitterMessage,
-			},
+		// we are here, then we got the good data. pipe it forward.
+		var sent bool
+		if received, ok := data.(*MessageReceived); ok {
+			sent = stream.deliver(ctx, received.Message)
+		} else {
+			sent = stream.send(ctx, Event{Data: data})
+		}
+		if !sent {
+			return ctx.Err()
 		}
 	}
+}
+
+// deliver pipes a received message forward unless it was delivered before.
+func (stream *Stream) deliver(ctx context.Context, message Message) bool {
+	if message.ID != "" {
+		if !stream.seen.add(message.ID) {
+			stream.gitter.log("Dropped duplicate message " + message.ID)
+			return true
+		}
+		stream.lastID = message.ID
+	}
+	return stream.send(ctx, Event{
+		Data: &MessageReceived{
+			Message: message,
+		},
+	})
+}
+
+// backfill delivers the messages posted after the last delivered one, which
+// were missed while the connection was down.
+func (stream *Stream) backfill(ctx context.Context) {
+	if stream.lastID == "" {
+		return
+	}
 
-	gitter.log("Listening was completed")
+	it := stream.gitter.NewMessageIteratorContext(ctx, stream.roomID, &MessageIteratorOptions{
+		Direction: Forward,
+		StartID:   stream.lastID,
+	})
+	for it.Next() {
+		if !stream.deliver(ctx, it.Message()) {
+			return
+		}
+	}
+	if err := it.Err(); err != nil {
+		stream.gitter.log("Failed to fetch missed messages: " + err.Error())
+		stream.send(ctx, Event{Data: &StreamError{Err: err}})
+	}
+}
+
+// send pipes the event to the Event channel unless ctx is done first.
+func (stream *Stream) send(ctx context.Context, event Event) bool {
+	select {
+	case stream.Event <- event:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
 
 // Stream holds stream data.
 type Stream struct {
-	url              string
-	Event            chan Event
-	streamConnection *streamConnection
-	gitter           *Gitter
+	url     string
+	roomID  string
+	Event   chan Event
+	gitter  *Gitter
+	options StreamOptions
+
+	// last delivered message and recently delivered ones, only used by Run
+	lastID string
+	seen   *recentIDs
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	cancel  context.CancelFunc
+
+	destroyOnce sync.Once
+}
+
+// sendClosed sends the GitterConnectionClosed event. Once ctx is done nobody
+// may be reading anymore, so the send gives up after closedEventTimeout
+// rather than blocking shutdown.
+func (stream *Stream) sendClosed(ctx context.Context) {
+	event := Event{
+		Data: &GitterConnectionClosed{},
+	}
+	if ctx.Err() == nil {
+		stream.send(ctx, event)
+		return
+	}
+
+	timer := time.NewTimer(closedEventTimeout)
+	defer timer.Stop()
+	select {
+	case stream.Event <- event:
+	case <-timer.C:
+	}
 }
 
 func (stream *Stream) destroy() {
-	close(stream.Event)
-	stream.streamConnection.currentRetries = 0
+	stream.destroyOnce.Do(func() {
+		close(stream.Event)
+	})
 }
 
+// Event holds one of the event types below in Data.
 type Event struct {
 	Data interface{}
 }
@@ -126,92 +355,156 @@ type MessageReceived struct {
 	Message Message
 }
 
-// connect and try to reconnect with
-func (stream *Stream) connect() {
+// MessageUpdated is sent when a message was edited.
+type MessageUpdated struct {
+	Message Message
+}
 
-	if stream.streamConnection.retries == stream.streamConnection.currentRetries {
-		stream.Close()
-		stream.gitter.log("Number of retries exceeded the max retries number, we are done here")
-		return
-	}
+// MessageRemoved is sent when a message was deleted.
+type MessageRemoved struct {
+	MessageID string
+}
 
-	res, err := stream.gitter.getResponse(stream.url, stream)
-	if err != nil || res.StatusCode != 200 {
-		stream.gitter.log("Failed to get response, trying reconnect")
-		if res != nil {
-			stream.gitter.log(fmt.Sprintf("Status code: %v", res.StatusCode))
-		}
-		stream.gitter.log(err)
+// MessagePatched is sent when some fields of a message changed.
+// Message only holds the ID and the changed fields.
+type MessagePatched struct {
+	Message Message
+}
 
-		// sleep and wait
-		stream.streamConnection.currentRetries++
-		time.Sleep(time.Millisecond * stream.streamConnection.wait * time.Duration(stream.streamConnection.currentRetries))
+// ReadByChanged is sent when the number of users that read a message changed.
+type ReadByChanged struct {
+	MessageID string
+	ReadBy    int
+}
 
-		// connect again
-		stream.Close()
-		stream.connect()
-	} else {
-		stream.gitter.log("Response was received")
-		stream.streamConnection.currentRetries = 0
-		stream.streamConnection.closed = false
-		stream.streamConnection.response = res
-	}
+// StreamError is sent when streamed data could not be decoded or missed
+// messages could not be fetched. The stream keeps running.
+type StreamError struct {
+	Err error
 }
 
-type streamConnection struct {
+// Reconnecting is sent before every attempt to restore the stream connection.
+type Reconnecting struct {
 
-	// connection was closed
-	closed bool
+	// Number of the attempt, starting at 1
+	Attempt int
 
-	// wait time till next try
-	wait time.Duration
+	// Time waited before the attempt
+	Delay time.Duration
 
-	// max tries to recover
-	retries int
+	// Error that dropped the connection or failed the previous attempt
+	Err error
+}
 
-	// current streamed response
-	response *http.Response
+// Reconnected is sent once the stream connection is restored.
+type Reconnected struct {
 
-	// current request
-	request *http.Request
+	// Number of attempts it took
+	Attempts int
+}
 
-	// current status
-	currentRetries int
+// connect opens one streaming connection.
+func (stream *Stream) connect(ctx context.Context) (*http.Response, error) {
+
+	res, err := stream.gitter.getResponse(ctx, stream.url)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		err = newAPIError(res.Request, res)
+		res.Body.Close()
+		stream.gitter.log(err)
+		return nil, err
+	}
+
+	stream.gitter.log("Response was received")
+	return res, nil
 }
 
-// Close the stream connection and stop receiving streamed data
+// Close the stream connection and stop receiving streamed data.
+// It is safe to call from any goroutine and more than once.
 func (stream *Stream) Close() {
-	conn := stream.streamConnection
-	conn.closed = true
-	if conn.response != nil {
-		stream.gitter.log("Stream connection close response")
-		defer conn.response.Body.Close()
-	}
-	if conn.request != nil {
-		stream.gitter.log("Stream connection close request")
-		switch transport := stream.gitter.config.client.Transport.(type) {
-		case *httpclient.Transport:
-			transport.CancelRequest(conn.request)
-		default:
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	stream.closed = true
+	if stream.cancel != nil {
+		stream.gitter.log("Stream connection close")
+		stream.cancel()
+	}
+}
+
+// resumeHistorySize is the number of delivered message IDs remembered to
+// drop duplicates.
+const resumeHistorySize = 1000
+
+// decodeEvent decodes streamed data into an event. Data is either a plain
+// message or an operation envelope, e.g. {"operation": "update", "model": {...}}.
+func decodeEvent(data []byte) (interface{}, error) {
+
+	var envelope struct {
+		Operation string          `json:"operation"`
+		Model     json.RawMessage `json:"model"`
+	}
+	err := json.Unmarshal(data, &envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	model := []byte(envelope.Model)
+	if envelope.Operation == "" {
+		model = data
+	}
+
+	var message Message
+	err = json.Unmarshal(model, &message)
+	if err != nil {
+		return nil, err
+	}
+
+	switch envelope.Operation {
+	case "", "create":
+		return &MessageReceived{Message: message}, nil
+	case "update":
+		return &MessageUpdated{Message: message}, nil
+	case "remove":
+		return &MessageRemoved{MessageID: message.ID}, nil
+	case "patch":
+		var fields map[string]json.RawMessage
+		json.Unmarshal(model, &fields)
+		delete(fields, "id")
+		if _, ok := fields["readBy"]; ok && len(fields) == 1 {
+			return &ReadByChanged{MessageID: message.ID, ReadBy: message.ReadBy}, nil
 		}
+		return &MessagePatched{Message: message}, nil
 	}
+	return nil, fmt.Errorf("unknown operation %q", envelope.Operation)
 }
 
-func (stream *Stream) isClosed() bool {
-	return stream.streamConnection.closed
+// recentIDs is a fixed size set of the most recently added IDs.
+type recentIDs struct {
+	ids  []string
+	set  map[string]bool
+	next int
 }
 
-func (stream *Stream) getResponse() *http.Response {
-	return stream.streamConnection.response
+func newRecentIDs(size int) *recentIDs {
+	return &recentIDs{
+		ids: make([]string, size),
+		set: make(map[string]bool, size),
+	}
 }
 
-// Optional, set stream connection properties
-// wait - time in milliseconds of waiting between reconnections. Will grow exponentially.
-// retries - number of reconnections retries before dropping the stream.
-func (gitter *Gitter) newStreamConnection(wait time.Duration, retries int) *streamConnection {
-	return &streamConnection{
-		closed:  true,
-		wait:    wait,
-		retries: retries,
+// add remembers the ID, forgetting the oldest one if full. It returns false
+// if the ID was already known.
+func (recent *recentIDs) add(id string) bool {
+	if recent.set[id] {
+		return false
+	}
+	if oldest := recent.ids[recent.next]; oldest != "" {
+		delete(recent.set, oldest)
 	}
+	recent.ids[recent.next] = id
+	recent.set[id] = true
+	recent.next = (recent.next + 1) % len(recent.ids)
+	return true
 }
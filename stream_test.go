@@ -0,0 +1,649 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serveStream fakes the stream of room xyz. Every connection writes the next
+// body in one chunk and ends. An empty body, or a connection past the
+// last body, fails with a server error. No messages are missed between
+// connections.
+func serveStream(t *testing.T, bodies ...string) *int32 {
+	var calls int32
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("afterId") != "" {
+			fmt.Fprint(w, "[]")
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			t.Errorf("Expected %v, got %v", "Bearer abc", r.Header.Get("Authorization"))
+		}
+
+		n := int(atomic.AddInt32(&calls, 1))
+		if n > len(bodies) || bodies[n-1] == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, bodies[n-1])
+		w.(http.Flusher).Flush()
+	})
+	return &calls
+}
+
+// newTestStream returns a stream of room xyz that retries quickly.
+func newTestStream() *Stream {
+	return gitter.StreamWithOptions("xyz", StreamOptions{
+		Backoff:    time.Millisecond,
+		MaxRetries: 2,
+	})
+}
+
+// collectEvents reads events until the stream closes its Event channel.
+func collectEvents(t *testing.T, stream *Stream) []Event {
+	var events []Event
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-stream.Event:
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		case <-timeout:
+			t.Fatal("Timed out waiting for stream events")
+		}
+	}
+}
+
+// receivedIDs returns the IDs of the received messages and fails unless the
+// last event reports the closed connection.
+func receivedIDs(t *testing.T, events []Event) []string {
+	var ids []string
+	for i, event := range events {
+		switch data := event.Data.(type) {
+		case *MessageReceived:
+			ids = append(ids, data.Message.ID)
+		case *GitterConnectionClosed:
+			if i != len(events)-1 {
+				t.Errorf("Expected %T as last event, got it at %v", data, i)
+			}
+		}
+	}
+
+	if len(events) == 0 {
+		t.Fatalf("Expected events, got none")
+	}
+
+	if _, ok := events[len(events)-1].Data.(*GitterConnectionClosed); !ok {
+		t.Errorf("Expected %T, got %T", &GitterConnectionClosed{}, events[len(events)-1].Data)
+	}
+	return ids
+}
+
+func TestStream_url(t *testing.T) {
+	setup()
+	defer teardown()
+
+	stream := gitter.Stream("xyz")
+	if stream.url != server.URL+"/rooms/xyz/chatMessages" {
+		t.Errorf("Expected %v, got %v", server.URL+"/rooms/xyz/chatMessages", stream.url)
+	}
+}
+
+func TestStreamWithOptions_zeroValue(t *testing.T) {
+	setup()
+	defer teardown()
+
+	defaults := DefaultStreamOptions()
+
+	stream := gitter.StreamWithOptions("xyz", StreamOptions{})
+	if stream.options.Backoff != defaults.Backoff {
+		t.Errorf("Expected %v, got %v", defaults.Backoff, stream.options.Backoff)
+	}
+
+	if stream.options.MaxRetries != defaults.MaxRetries {
+		t.Errorf("Expected %v, got %v", defaults.MaxRetries, stream.options.MaxRetries)
+	}
+
+	stream = gitter.StreamWithOptions("xyz", StreamOptions{MaxRetries: UnlimitedRetries})
+	if stream.options.Backoff != defaults.Backoff {
+		t.Errorf("Expected %v, got %v", defaults.Backoff, stream.options.Backoff)
+	}
+
+	if stream.options.MaxRetries != UnlimitedRetries {
+		t.Errorf("Expected %v, got %v", UnlimitedRetries, stream.options.MaxRetries)
+	}
+}
+
+func TestListen_fakeServer(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, `{"id": "666", "text": "hello"}`+"\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	events := collectEvents(t, stream)
+	if len(events) < 2 {
+		t.Fatalf("Expected at least %v, got %v", 2, len(events))
+	}
+
+	received, ok := events[0].Data.(*MessageReceived)
+	if !ok {
+		t.Fatalf("Expected %T, got %T", received, events[0].Data)
+	}
+
+	if received.Message.ID != "666" {
+		t.Errorf("Expected %v, got %v", "666", received.Message.ID)
+	}
+
+	if received.Message.Text != "hello" {
+		t.Errorf("Expected %v, got %v", "hello", received.Message.Text)
+	}
+
+	if _, ok := events[len(events)-1].Data.(*GitterConnectionClosed); !ok {
+		t.Errorf("Expected %T, got %T", &GitterConnectionClosed{}, events[len(events)-1].Data)
+	}
+}
+
+func TestListen_multipleMessagesInChunk(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, `{"id": "1"}`+"\n"+`{"id": "2"}`+"\n"+`{"id": "3"}`+"\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	wanted := []string{"1", "2", "3"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestListen_keepalives(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, " \n\n"+`{"id": "1"}`+"\n \r\n\n"+`{"id": "2"}`+"\n\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	wanted := []string{"1", "2"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestListen_reconnectAfterDisconnect(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := serveStream(t, `{"id": "1"}`+"\n", `{"id": "2"}`+"\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	wanted := []string{"1", "2"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+
+	// two streamed connections and two failed reconnection attempts
+	if n := atomic.LoadInt32(calls); n != 4 {
+		t.Errorf("Expected %v, got %v", 4, n)
+	}
+}
+
+func TestListen_reconnectEvents(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, `{"id": "1"}`+"\n", "", `{"id": "2"}`+"\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	var kinds []string
+	for _, event := range collectEvents(t, stream) {
+		switch data := event.Data.(type) {
+		case *MessageReceived:
+			kinds = append(kinds, "message")
+		case *Reconnecting:
+			kinds = append(kinds, fmt.Sprintf("reconnecting %v", data.Attempt))
+			if data.Err == nil {
+				t.Errorf("Expected error, got %v", data.Err)
+			}
+		case *Reconnected:
+			kinds = append(kinds, fmt.Sprintf("reconnected %v", data.Attempts))
+		case *GitterConnectionClosed:
+			kinds = append(kinds, "closed")
+		}
+	}
+
+	wanted := []string{
+		"message",
+		"reconnecting 1", // connection dropped
+		"reconnecting 2", // server error
+		"reconnected 2",
+		"message",
+		"reconnecting 1",
+		"reconnecting 2",
+		"closed",
+	}
+	if !reflect.DeepEqual(kinds, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, kinds)
+	}
+}
+
+func TestListen_unlimitedRetries(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, "", "", "", "", "", "", `{"id": "1"}`+"\n")
+
+	stream := gitter.StreamWithOptions("xyz", StreamOptions{
+		Backoff:    time.Millisecond,
+		MaxRetries: UnlimitedRetries,
+	})
+	result := runStream(context.Background(), stream)
+
+	for event := range stream.Event {
+		if _, ok := event.Data.(*MessageReceived); ok {
+			stream.Close()
+		}
+	}
+
+	if err := waitResult(t, result); err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestListen_reconnectAfterServerError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, "", `{"id": "1"}`+"\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	wanted := []string{"1"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestListen_invalidMessage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, `{"id": `+"\n"+`{"id": "1"}`+"\n")
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	events := collectEvents(t, stream)
+	ids := receivedIDs(t, events)
+	wanted := []string{"1"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+
+	if _, ok := events[0].Data.(*StreamError); !ok {
+		t.Errorf("Expected %T, got %T", &StreamError{}, events[0].Data)
+	}
+}
+
+func TestListen_operations(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t, `{"operation": "create", "model": {"id": "1", "text": "hi"}}
+{"operation": "update", "model": {"id": "1", "text": "hello"}}
+{"operation": "patch", "model": {"id": "1", "readBy": 2}}
+{"operation": "remove", "model": {"id": "1"}}
+`)
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	var got []interface{}
+	for _, event := range collectEvents(t, stream) {
+		switch event.Data.(type) {
+		case *MessageReceived, *MessageUpdated, *ReadByChanged, *MessageRemoved:
+			got = append(got, event.Data)
+		}
+	}
+
+	wanted := []interface{}{
+		&MessageReceived{Message: Message{ID: "1", Text: "hi"}},
+		&MessageUpdated{Message: Message{ID: "1", Text: "hello"}},
+		&ReadByChanged{MessageID: "1", ReadBy: 2},
+		&MessageRemoved{MessageID: "1"},
+	}
+	if !reflect.DeepEqual(got, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, got)
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	tests := []struct {
+		data   string
+		wanted interface{}
+	}{
+		{`{"id": "1", "text": "hi"}`, &MessageReceived{Message: Message{ID: "1", Text: "hi"}}},
+		{`{"operation": "create", "model": {"id": "1"}}`, &MessageReceived{Message: Message{ID: "1"}}},
+		{`{"operation": "update", "model": {"id": "1", "text": "hello"}}`, &MessageUpdated{Message: Message{ID: "1", Text: "hello"}}},
+		{`{"operation": "remove", "model": {"id": "1"}}`, &MessageRemoved{MessageID: "1"}},
+		{`{"operation": "patch", "model": {"id": "1", "readBy": 3}}`, &ReadByChanged{MessageID: "1", ReadBy: 3}},
+		{`{"operation": "patch", "model": {"id": "1", "text": "edited", "readBy": 3}}`, &MessagePatched{Message: Message{ID: "1", Text: "edited", ReadBy: 3}}},
+	}
+
+	for _, test := range tests {
+		got, err := decodeEvent([]byte(test.data))
+		if err != nil {
+			t.Errorf("Expected nil, got %v", err)
+			continue
+		}
+		if !reflect.DeepEqual(got, test.wanted) {
+			t.Errorf("Expected %v, got %v", test.wanted, got)
+		}
+	}
+
+	if _, err := decodeEvent([]byte(`{"operation": "unknown", "model": {"id": "1"}}`)); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+}
+
+// serveBlockingStream fakes the stream of room xyz with a connection that
+// sends one message and stays open until the client goes away.
+func serveBlockingStream() {
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1"}`+"\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+}
+
+// runStream runs the stream in the background and returns the result of Run.
+func runStream(ctx context.Context, stream *Stream) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		result <- stream.Run(ctx)
+	}()
+	return result
+}
+
+// waitResult waits for the result of Run.
+func waitResult(t *testing.T, result <-chan error) error {
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for Run to return")
+		return nil
+	}
+}
+
+func TestStream_runContextCanceled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveBlockingStream()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := newTestStream()
+	result := runStream(ctx, stream)
+
+	event := <-stream.Event
+	if _, ok := event.Data.(*MessageReceived); !ok {
+		t.Fatalf("Expected %T, got %T", &MessageReceived{}, event.Data)
+	}
+
+	cancel()
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	if len(ids) != 0 {
+		t.Errorf("Expected %v, got %v", 0, len(ids))
+	}
+
+	if err := waitResult(t, result); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestStream_runContextCanceledWithoutReader(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveBlockingStream()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := newTestStream()
+	result := runStream(ctx, stream)
+
+	event := <-stream.Event
+	if _, ok := event.Data.(*MessageReceived); !ok {
+		t.Fatalf("Expected %T, got %T", &MessageReceived{}, event.Data)
+	}
+
+	// cancel and stop reading, Run must return anyway
+	cancel()
+
+	if err := waitResult(t, result); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	if _, ok := <-stream.Event; ok {
+		t.Errorf("Expected closed channel")
+	}
+}
+
+func TestStream_close(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveBlockingStream()
+
+	stream := newTestStream()
+	result := runStream(context.Background(), stream)
+
+	<-stream.Event
+	stream.Close()
+
+	receivedIDs(t, collectEvents(t, stream))
+
+	if err := waitResult(t, result); err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestStream_closeWithoutReader(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// several messages are pending when the stream is closed
+	serveStream(t, `{"id": "1"}`+"\n"+`{"id": "2"}`+"\n"+`{"id": "3"}`+"\n")
+
+	stream := newTestStream()
+	result := runStream(context.Background(), stream)
+
+	<-stream.Event
+	stream.Close()
+
+	receivedIDs(t, collectEvents(t, stream))
+	waitResult(t, result)
+}
+
+func TestStream_closeBeforeRun(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveBlockingStream()
+
+	stream := newTestStream()
+	stream.Close()
+	result := runStream(context.Background(), stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	if len(ids) != 0 {
+		t.Errorf("Expected %v, got %v", 0, len(ids))
+	}
+
+	if err := waitResult(t, result); err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
+
+func TestStream_concurrentClose(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveBlockingStream()
+
+	stream := newTestStream()
+	result := runStream(context.Background(), stream)
+
+	<-stream.Event
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream.Close()
+		}()
+	}
+
+	receivedIDs(t, collectEvents(t, stream))
+	wg.Wait()
+	waitResult(t, result)
+}
+
+func TestStream_runTwice(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveStream(t)
+
+	stream := newTestStream()
+	result := runStream(context.Background(), stream)
+	receivedIDs(t, collectEvents(t, stream))
+
+	var apiErr APIError
+	if err := waitResult(t, result); !errors.As(err, &apiErr) {
+		t.Errorf("Expected %T, got %v", apiErr, err)
+	}
+
+	if err := stream.Run(context.Background()); err != ErrStreamStarted {
+		t.Errorf("Expected %v, got %v", ErrStreamStarted, err)
+	}
+}
+
+// serveResumableStream fakes the stream of room xyz together with its
+// message history. The first connection streams messages 1 and 2 and drops,
+// message 3 is posted meanwhile and the second connection streams message 4,
+// which the history already holds too.
+func serveResumableStream() *int32 {
+	var calls, backfills int32
+	mux.HandleFunc("/rooms/xyz/chatMessages", func(w http.ResponseWriter, r *http.Request) {
+		if afterID := r.URL.Query().Get("afterId"); afterID != "" {
+			atomic.AddInt32(&backfills, 1)
+			messages := []Message{}
+			if afterID == "2" {
+				messages = append(messages, Message{ID: "3"}, Message{ID: "4"})
+			}
+			json.NewEncoder(w).Encode(messages)
+			return
+		}
+
+		switch atomic.AddInt32(&calls, 1) {
+		case 1:
+			fmt.Fprint(w, `{"id": "1"}`+"\n"+`{"id": "2"}`+"\n")
+		case 2:
+			fmt.Fprint(w, `{"id": "4"}`+"\n")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	return &backfills
+}
+
+func TestStream_resume(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveResumableStream()
+
+	stream := newTestStream()
+	go gitter.Listen(stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	wanted := []string{"1", "2", "3", "4"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestStream_disableResume(t *testing.T) {
+	setup()
+	defer teardown()
+
+	backfills := serveResumableStream()
+
+	stream := gitter.StreamWithOptions("xyz", StreamOptions{
+		Backoff:       time.Millisecond,
+		MaxRetries:    2,
+		DisableResume: true,
+	})
+	go gitter.Listen(stream)
+
+	ids := receivedIDs(t, collectEvents(t, stream))
+	wanted := []string{"1", "2", "4"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+
+	if n := atomic.LoadInt32(backfills); n != 0 {
+		t.Errorf("Expected %v, got %v", 0, n)
+	}
+}
+
+func TestRecentIDs(t *testing.T) {
+	recent := newRecentIDs(2)
+
+	if !recent.add("1") || !recent.add("2") {
+		t.Errorf("Expected new IDs to be added")
+	}
+
+	if recent.add("2") {
+		t.Errorf("Expected %v to be known", "2")
+	}
+
+	// evicts 1
+	recent.add("3")
+	if !recent.add("1") {
+		t.Errorf("Expected %v to be forgotten", "1")
+	}
+}
@@ -3,7 +3,7 @@ package gitter
 import (
 	"net/http"
 	"net/http/httptest"
-	"net/url"
+	"time"
 )
 
 var (
@@ -16,13 +16,18 @@ func setup() {
 	mux = http.NewServeMux()
 	server = httptest.NewServer(mux)
 
-	gitter = New("abc")
+	// Keep retries but don't slow the tests down.
+	retryPolicy := DefaultRetryPolicy()
+	retryPolicy.BaseDelay = time.Millisecond
 
-	// Fake the API and Stream base URLs by using the test
+	// Fake the API, Stream and Faye base URLs by using the test
 	// server URL instead.
-	url, _ := url.Parse(server.URL)
-	gitter.config.apiBaseURL = url.String() + "/"
-	gitter.config.streamBaseURL = url.String() + "/"
+	gitter = NewWithOptions("abc",
+		WithAPIBaseURL(server.URL),
+		WithStreamBaseURL(server.URL),
+		WithFayeBaseURL(server.URL+"/faye"),
+		WithRetryPolicy(retryPolicy),
+	)
 }
 
 func teardown() {
@@ -0,0 +1,55 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetUnreadItems returns the IDs of the unread messages and mentions of the user in a room
+func (gitter *Gitter) GetUnreadItems(userID, roomID string) (*UnreadItems, error) {
+	return gitter.GetUnreadItemsContext(context.Background(), userID, roomID)
+}
+
+// GetUnreadItemsContext is like GetUnreadItems but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUnreadItemsContext(ctx context.Context, userID, roomID string) (*UnreadItems, error) {
+
+	var items UnreadItems
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user/"+userID+"/rooms/"+roomID+"/unreadItems")
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &items)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &items, nil
+}
+
+// MarkAsRead marks the messages of a room as read by the user
+func (gitter *Gitter) MarkAsRead(userID, roomID string, messageIDs ...string) error {
+	return gitter.MarkAsReadContext(context.Background(), userID, roomID, messageIDs...)
+}
+
+// MarkAsReadContext is like MarkAsRead but uses ctx for the underlying requests.
+func (gitter *Gitter) MarkAsReadContext(ctx context.Context, userID, roomID string, messageIDs ...string) error {
+
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	items := struct {
+		Chat []string `json:"chat"`
+	}{messageIDs}
+	body, _ := json.Marshal(items)
+	_, err := gitter.post(ctx, gitter.config.apiBaseURL+"user/"+userID+"/rooms/"+roomID+"/unreadItems", body)
+	if err != nil {
+		gitter.log(err)
+		return err
+	}
+
+	return nil
+}
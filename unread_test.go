@@ -0,0 +1,60 @@
+package gitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestGetUnreadItems(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/rooms/xyz/unreadItems", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "chat": ["666", "667"],
+                "mention": ["667"]
+            }
+        `)
+	})
+
+	items, err := gitter.GetUnreadItems("abc", "xyz")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if !reflect.DeepEqual(items.Chat, []string{"666", "667"}) {
+		t.Errorf("Expected %v, got %v", []string{"666", "667"}, items.Chat)
+	}
+
+	if !reflect.DeepEqual(items.Mention, []string{"667"}) {
+		t.Errorf("Expected %v, got %v", []string{"667"}, items.Mention)
+	}
+}
+
+func TestMarkAsRead(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user/abc/rooms/xyz/unreadItems", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("Expected %v, got %v", "POST", r.Method)
+		}
+
+		var items UnreadItems
+		json.NewDecoder(r.Body).Decode(&items)
+		if !reflect.DeepEqual(items.Chat, []string{"666", "667"}) {
+			t.Errorf("Expected %v, got %v", []string{"666", "667"}, items.Chat)
+		}
+
+		fmt.Fprint(w, `{"success": true}`)
+	})
+
+	err := gitter.MarkAsRead("abc", "xyz", "666", "667")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+}
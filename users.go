@@ -0,0 +1,149 @@
+package gitter
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// RoleAdmin is the role of room administrators
+const RoleAdmin = "admin"
+
+// RoomUsersOptions filters and paginates the users of a room
+type RoomUsersOptions struct {
+
+	// Only return users matching the query
+	Query string
+
+	// Skip n users
+	Skip int
+
+	// Maximum number of users to return
+	Limit int
+}
+
+func (options *RoomUsersOptions) encode() string {
+	values := url.Values{}
+
+	if options.Query != "" {
+		values.Add("q", options.Query)
+	}
+
+	if options.Skip > 0 {
+		values.Add("skip", strconv.Itoa(options.Skip))
+	}
+
+	if options.Limit > 0 {
+		values.Add("limit", strconv.Itoa(options.Limit))
+	}
+
+	return values.Encode()
+}
+
+// ListRoomUsers returns one page of the users in a room.
+// Options are optional. You can pass nil or specific options.
+func (gitter *Gitter) ListRoomUsers(roomID string, options *RoomUsersOptions) ([]User, error) {
+	return gitter.ListRoomUsersContext(context.Background(), roomID, options)
+}
+
+// ListRoomUsersContext is like ListRoomUsers but uses ctx for the underlying requests.
+func (gitter *Gitter) ListRoomUsersContext(ctx context.Context, roomID string, options *RoomUsersOptions) ([]User, error) {
+
+	var users []User
+	url := gitter.config.apiBaseURL + "rooms/" + roomID + "/users"
+	if options != nil {
+		url += "?" + options.encode()
+	}
+	response, err := gitter.get(ctx, url)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &users)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// GetUserByUsername returns the user with the passed username
+func (gitter *Gitter) GetUserByUsername(username string) (*User, error) {
+	return gitter.GetUserByUsernameContext(context.Background(), username)
+}
+
+// GetUserByUsernameContext is like GetUserByUsername but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserByUsernameContext(ctx context.Context, username string) (*User, error) {
+
+	var user User
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"users/"+url.PathEscape(username))
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &user)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetUserProfile returns the extended profile of the user with the passed username
+func (gitter *Gitter) GetUserProfile(username string) (*UserProfile, error) {
+	return gitter.GetUserProfileContext(context.Background(), username)
+}
+
+// GetUserProfileContext is like GetUserProfile but uses ctx for the underlying requests.
+func (gitter *Gitter) GetUserProfileContext(ctx context.Context, username string) (*UserProfile, error) {
+
+	var profile UserProfile
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"users/"+url.PathEscape(username))
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &profile)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return &profile, nil
+}
+
+// SearchUsers queries the Users resources of gitter API
+func (gitter *Gitter) SearchUsers(query string) ([]User, error) {
+	return gitter.SearchUsersContext(context.Background(), query)
+}
+
+// SearchUsersContext is like SearchUsers but uses ctx for the underlying requests.
+func (gitter *Gitter) SearchUsersContext(ctx context.Context, query string) ([]User, error) {
+
+	var users struct {
+		Results []User `json:"results"`
+	}
+
+	values := url.Values{}
+	values.Add("q", query)
+	values.Add("type", "gitter")
+	response, err := gitter.get(ctx, gitter.config.apiBaseURL+"user?"+values.Encode())
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	err = json.Unmarshal(response, &users)
+	if err != nil {
+		gitter.log(err)
+		return nil, err
+	}
+
+	return users.Results, nil
+}
@@ -0,0 +1,193 @@
+package gitter
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// serveRoomUsers fakes the users endpoint of room xyz for a list of user IDs.
+func serveRoomUsers(ids []string) {
+	mux.HandleFunc("/rooms/xyz/users", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		skip, _ := strconv.Atoi(query.Get("skip"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+
+		users := []User{}
+		for i := skip; i < len(ids) && i < skip+limit; i++ {
+			users = append(users, User{ID: ids[i]})
+		}
+		json.NewEncoder(w).Encode(users)
+	})
+}
+
+func TestListRoomUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/rooms/xyz/users", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("q") != "foo" {
+			t.Errorf("Expected %v, got %v", "foo", query.Get("q"))
+		}
+
+		if query.Get("skip") != "30" {
+			t.Errorf("Expected %v, got %v", "30", query.Get("skip"))
+		}
+
+		if query.Get("limit") != "10" {
+			t.Errorf("Expected %v, got %v", "10", query.Get("limit"))
+		}
+
+		fmt.Fprint(w, `
+            [
+                {
+                    "id": "123",
+                    "username": "fooBar",
+                    "role": "admin"
+                }
+            ]
+        `)
+	})
+
+	u, err := gitter.ListRoomUsers("xyz", &RoomUsersOptions{Query: "foo", Skip: 30, Limit: 10})
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(u) != 1 {
+		t.Fatalf("Expected %v, got %v", 1, len(u))
+	}
+
+	if u[0].Role != RoleAdmin {
+		t.Errorf("Expected %v, got %v", RoleAdmin, u[0].Role)
+	}
+}
+
+func TestRoomUserIterator(t *testing.T) {
+	setup()
+	defer teardown()
+
+	serveRoomUsers([]string{"1", "2", "3", "4", "5"})
+
+	it := gitter.NewRoomUserIterator("xyz", &RoomUsersOptions{Limit: 2})
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.User().ID)
+	}
+
+	if it.Err() != nil {
+		t.Errorf("Expected %v, got %v", nil, it.Err())
+	}
+
+	wanted := []string{"1", "2", "3", "4", "5"}
+	if !reflect.DeepEqual(ids, wanted) {
+		t.Errorf("Expected %v, got %v", wanted, ids)
+	}
+}
+
+func TestGetUserByUsername(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/fooBar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "id": "123",
+                "username": "fooBar",
+                "gv": "3"
+            }
+        `)
+	})
+
+	u, err := gitter.GetUserByUsername("fooBar")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if u.ID != "123" {
+		t.Errorf("Expected %v, got %v", "123", u.ID)
+	}
+
+	if u.GravatarVersion != "3" {
+		t.Errorf("Expected %v, got %v", "3", u.GravatarVersion)
+	}
+}
+
+func TestGetUserProfile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/users/fooBar", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `
+            {
+                "id": "123",
+                "username": "fooBar",
+                "company": "Acme",
+                "location": "Berlin",
+                "profile": "https://github.com/fooBar",
+                "github": {
+                    "followers": 10,
+                    "public_repos": 5
+                }
+            }
+        `)
+	})
+
+	p, err := gitter.GetUserProfile("fooBar")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if p.Username != "fooBar" {
+		t.Errorf("Expected %v, got %v", "fooBar", p.Username)
+	}
+
+	if p.Company != "Acme" {
+		t.Errorf("Expected %v, got %v", "Acme", p.Company)
+	}
+
+	if p.Location != "Berlin" {
+		t.Errorf("Expected %v, got %v", "Berlin", p.Location)
+	}
+
+	if p.Github.PublicRepos != 5 {
+		t.Errorf("Expected %v, got %v", 5, p.Github.PublicRepos)
+	}
+}
+
+func TestSearchUsers(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "foo bar" {
+			t.Errorf("Expected %v, got %v", "foo bar", r.URL.Query().Get("q"))
+		}
+
+		fmt.Fprint(w, `
+            {
+                "results": [
+                    {"id": "123", "username": "fooBar"},
+                    {"id": "456", "username": "fooBaz"}
+                ]
+            }
+        `)
+	})
+
+	u, err := gitter.SearchUsers("foo bar")
+	if err != nil {
+		t.Errorf("Expected %v, got %v", nil, err)
+	}
+
+	if len(u) != 2 {
+		t.Fatalf("Expected %v, got %v", 2, len(u))
+	}
+
+	if u[1].Username != "fooBaz" {
+		t.Errorf("Expected %v, got %v", "fooBaz", u[1].Username)
+	}
+}